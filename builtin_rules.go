@@ -0,0 +1,217 @@
+package enforcer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/rrojan/enforcer/enforcements"
+)
+
+var (
+	intKinds        = []reflect.Kind{reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64}
+	uintKinds       = []reflect.Kind{reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64}
+	numberKinds     = append(append([]reflect.Kind{}, intKinds...), reflect.Float32, reflect.Float64)
+	boundedKinds    = append(append([]reflect.Kind{}, numberKinds...), reflect.String)
+	enumKinds       = append(append([]reflect.Kind{}, numberKinds...), reflect.String)
+	coordinateKinds = append(append([]reflect.Kind{}, boundedKinds...), uintKinds...)
+	countryKinds    = append(append([]reflect.Kind{reflect.String}, intKinds...), uintKinds...)
+	pointKinds      = []reflect.Kind{reflect.String, reflect.Slice, reflect.Array}
+)
+
+// failure turns the message of an enforcements handler into an error, where
+// an empty message means the value passed
+func failure(message string) error {
+	if message == "" {
+		return nil
+	}
+	return errors.New(message)
+}
+
+// fieldErr passes on the *FieldError of a handler without turning a nil
+// pointer into a non-nil error
+func fieldErr(err *FieldError) error {
+	if err == nil {
+		return nil
+	}
+	return err
+}
+
+// builtin adapts the handler of a built-in rule. The parameter is checked by
+// enforcements.ParseParam when a tag is compiled and by CheckParamFor against
+// each value, so a mistake in it is a *ConfigError and anything the handler
+// reports is a failure of the value.
+func builtin(name string, kinds []reflect.Kind, handler func(fc FieldContext) error) Rule {
+	return Rule{
+		Name:  name,
+		Kinds: kinds,
+		Parse: func(param string) (interface{}, error) {
+			return nil, enforcements.ParseParam(name, param)
+		},
+		Check: func(fc FieldContext) error {
+			if fc.Value.IsValid() {
+				if err := enforcements.CheckParamFor(name, fc.Param, fc.Value.Type(), fc.Sibling); err != nil {
+					return &ConfigError{Message: err.Error()}
+				}
+			}
+			return handler(fc)
+		},
+	}
+}
+
+// stringRule adapts a handler that checks the value as text
+func stringRule(name string, handler func(value, fieldName, opt string) string) Rule {
+	return builtin(name, nil, func(fc FieldContext) error {
+		return failure(handler(fc.String(), fc.Field, fc.Opt))
+	})
+}
+
+// valueRule adapts a handler that checks the value itself, e.g. a time.Time
+func valueRule(name string, kinds []reflect.Kind, handler func(value interface{}, fieldName, opt string) string) Rule {
+	return builtin(name, kinds, func(fc FieldContext) error {
+		return failure(handler(fc.Value.Interface(), fc.Field, fc.Opt))
+	})
+}
+
+// timeRule adapts a handler that compares the value with the current time
+func timeRule(name string, handler func(value interface{}, fieldName, opt string, now time.Time) string) Rule {
+	return builtin(name, nil, func(fc FieldContext) error {
+		return failure(handler(fc.Value.Interface(), fc.Field, fc.Opt, fc.Now))
+	})
+}
+
+// boundedRule adapts the `between`, `min` and `max` handlers, which compare
+// durations, integers, floats and string lengths
+func boundedRule(
+	name string,
+	duration func(time.Duration, string, string) string,
+	integer func(int64, string, string) string,
+	float func(float64, string, string) string,
+	str func(string, string, string) string,
+) Rule {
+	return builtin(name, boundedKinds, func(fc FieldContext) error {
+		switch {
+		case fc.Value.Type() == enforcements.DurationType:
+			return failure(duration(time.Duration(fc.Value.Int()), fc.Field, fc.Opt))
+		case fc.Value.Kind() == reflect.String:
+			return failure(str(fc.Value.String(), fc.Field, fc.Opt))
+		case enforcements.IsFloatType(fc.Value.Kind()):
+			return failure(float(fc.Value.Float(), fc.Field, fc.Opt))
+		}
+		return failure(integer(fc.Value.Int(), fc.Field, fc.Opt))
+	})
+}
+
+func builtinRules() []Rule {
+	return []Rule{
+		{Name: "required", Check: func(fc FieldContext) error {
+			return failure(enforcements.HandleRequiredPresence(fc.Provided, fc.Field))
+		}},
+		{Name: "custom", Check: checkCustom},
+
+		boundedRule("between", enforcements.HandleBetweenDuration, enforcements.HandleBetweenInt, enforcements.HandleBetweenFloat, enforcements.HandleBetweenStr),
+		boundedRule("min", enforcements.HandleMinDuration, enforcements.HandleMinInt, enforcements.HandleMinFloat, enforcements.HandleMinStr),
+		boundedRule("max", enforcements.HandleMaxDuration, enforcements.HandleMaxInt, enforcements.HandleMaxFloat, enforcements.HandleMaxStr),
+		stringRule("wordCount", enforcements.HandleWordCount),
+		stringRule("match", enforcements.HandleMatch),
+		builtin("enum", enumKinds, func(fc FieldContext) error {
+			switch {
+			case fc.Value.Type() == enforcements.DurationType:
+				return failure(enforcements.HandleEnumDuration(time.Duration(fc.Value.Int()), fc.Field, fc.Opt))
+			case fc.Value.Kind() == reflect.String:
+				return failure(enforcements.HandleEnumStr(fc.Value.String(), fc.Field, fc.Opt))
+			case enforcements.IsFloatType(fc.Value.Kind()):
+				return failure(enforcements.HandleEnumIntOrFloat(fc.Value.Float(), fc.Field, fc.Opt))
+			}
+			return failure(enforcements.HandleEnumIntOrFloat(fc.Value.Int(), fc.Field, fc.Opt))
+		}),
+		builtin("exclude", enumKinds, func(fc FieldContext) error {
+			switch {
+			case fc.Value.Kind() == reflect.String:
+				return failure(enforcements.HandleExcludeStr(fc.Value.String(), fc.Field, fc.Opt))
+			case enforcements.IsFloatType(fc.Value.Kind()):
+				return failure(enforcements.HandleExcludeIntOrFloat(fc.Value.Float(), fc.Field, fc.Opt))
+			}
+			return failure(enforcements.HandleExcludeIntOrFloat(fc.Value.Int(), fc.Field, fc.Opt))
+		}),
+
+		builtin("latitude", coordinateKinds, func(fc FieldContext) error {
+			return failure(enforcements.HandleLatitude(fc.Value.Interface(), fc.Field))
+		}),
+		builtin("longitude", coordinateKinds, func(fc FieldContext) error {
+			return failure(enforcements.HandleLongitude(fc.Value.Interface(), fc.Field))
+		}),
+		builtin("latlng", nil, func(fc FieldContext) error {
+			return failure(enforcements.HandleLatLng(fc.String(), fc.Field))
+		}),
+		stringRule("geohash", enforcements.HandleGeohash),
+		valueRule("within", pointKinds, enforcements.HandleWithin),
+		builtin("phone", nil, func(fc FieldContext) error {
+			return failure(enforcements.HandlePhone(fc.String(), fc.Field, fc.Opt, fc.Sibling))
+		}),
+		builtin("postal", nil, func(fc FieldContext) error {
+			return failure(enforcements.HandlePostal(fc.String(), fc.Field, fc.Opt, fc.Sibling))
+		}),
+
+		valueRule("country", countryKinds, enforcements.HandleCountry),
+		builtin("language", nil, func(fc FieldContext) error {
+			return failure(enforcements.HandleLanguage(fc.String(), fc.Field))
+		}),
+		builtin("locale", nil, func(fc FieldContext) error {
+			return failure(enforcements.HandleLocale(fc.String(), fc.Field))
+		}),
+		builtin("timezone", nil, func(fc FieldContext) error {
+			return failure(enforcements.HandleTimezone(fc.String(), fc.Field))
+		}),
+		builtin("email", nil, func(fc FieldContext) error {
+			return failure(enforcements.HandleEmail(fc.Settings.CallContext(), fc.String(), fc.Field, fc.Opt))
+		}),
+		stringRule("url", enforcements.HandleURL),
+		builtin("eqfield", nil, func(fc FieldContext) error {
+			return failure(enforcements.HandleEqField(fc.Value, fc.Field, fc.Opt, fc.Sibling))
+		}),
+
+		stringRule("base64", enforcements.HandleBase64),
+		stringRule("base64url", enforcements.HandleBase64URL),
+		stringRule("hex", enforcements.HandleHex),
+		stringRule("json", enforcements.HandleJSON),
+		builtin("jwt", nil, func(fc FieldContext) error {
+			return failure(enforcements.HandleJWT(fc.String(), fc.Field))
+		}),
+
+		timeRule("before", enforcements.HandleBefore),
+		timeRule("after", enforcements.HandleAfter),
+		timeRule("betweenTime", enforcements.HandleBetweenTime),
+		builtin("datetime", nil, func(fc FieldContext) error {
+			return failure(enforcements.HandleDatetime(fc.String(), fc.Field, fc.Opt, fc.Sibling, fc.Now))
+		}),
+		builtin("minAge", nil, func(fc FieldContext) error {
+			return fieldErr(enforcements.HandleMinAge(fc.Value.Interface(), fc.Field, fc.Opt, fc.Now))
+		}),
+		builtin("maxAge", nil, func(fc FieldContext) error {
+			return fieldErr(enforcements.HandleMaxAge(fc.Value.Interface(), fc.Field, fc.Opt, fc.Now))
+		}),
+		stringRule("cron", enforcements.HandleCron),
+	}
+}
+
+// checkCustom runs the functions named by `custom:a,b`. They are only known to
+// CustomValidator, so other calls skip the rule.
+func checkCustom(fc FieldContext) error {
+	if fc.custom == nil {
+		return nil
+	}
+	var errs ValidationErrors
+	for _, name := range getCustomEnforcementNames(fc.Opt) {
+		enforcementFunc, ok := getCustomEnforcementFunc(fc.custom, name)
+		if !ok {
+			return &ConfigError{Message: fmt.Sprintf("Custom enforcement '%s' not found%s", name, enforcements.ForField(fc.Field))}
+		}
+		errs.add(fc.Field, fc.Opt, enforcementFunc(fc.String()))
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
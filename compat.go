@@ -0,0 +1,72 @@
+package enforcer
+
+import (
+	"strconv"
+	"strings"
+)
+
+// validateTagKey is the tag read by go-playground/validator
+const validateTagKey = "validate"
+
+// TranslateValidateTag maps a go-playground/validator `validate` tag, such as
+// `required,min=3,oneof=red green`, onto enforce rules. Parts without an
+// equivalent are returned in untranslated and left out of the rules.
+func TranslateValidateTag(tag string) (rules string, untranslated []string) {
+	// `-` skips the field
+	if tag == "-" {
+		return "", nil
+	}
+	var opts []string
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		opt, ok := translateValidateOption(part)
+		if !ok {
+			untranslated = append(untranslated, part)
+			continue
+		}
+		opts = append(opts, opt)
+	}
+	return strings.Join(opts, " "), untranslated
+}
+
+func translateValidateOption(part string) (string, bool) {
+	// `a|b` alternatives have no enforce equivalent
+	if strings.Contains(part, "|") {
+		return "", false
+	}
+	name, param, hasParam := strings.Cut(part, "=")
+	switch name {
+	case "required", "email", "url", "dive":
+		return name, !hasParam
+	case "omitempty":
+		return "optional", !hasParam
+	case "min", "max":
+		_, err := strconv.Atoi(param)
+		return name + ":" + param, err == nil
+	case "gte", "lte":
+		_, err := strconv.Atoi(param)
+		return map[string]string{"gte": "min", "lte": "max"}[name] + ":" + param, err == nil
+	case "gt", "lt":
+		// Exclusive bounds only translate for whole numbers
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return "", false
+		}
+		if name == "gt" {
+			return "min:" + strconv.Itoa(n+1), true
+		}
+		return "max:" + strconv.Itoa(n-1), true
+	case "len":
+		_, err := strconv.Atoi(param)
+		return "between:" + param + "," + param, err == nil
+	case "oneof":
+		values := strings.Fields(param)
+		return "enum:" + strings.Join(values, ","), len(values) > 0
+	case "eqfield":
+		return "eqfield:" + param, param != ""
+	}
+	return "", false
+}
@@ -0,0 +1,62 @@
+package enforcer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTranslateValidateTag(t *testing.T) {
+	tests := []struct {
+		tag              string
+		wantRules        string
+		wantUntranslated []string
+	}{
+		{"required,min=3,max=20", "required min:3 max:20", nil},
+		{"omitempty,email", "optional email", nil},
+		{"gte=1,lte=5", "min:1 max:5", nil},
+		{"gt=0,lt=10", "min:1 max:9", nil},
+		{"gt=0.5", "", []string{"gt=0.5"}},
+		{"len=4", "between:4,4", nil},
+		{"oneof=red green blue", "enum:red,green,blue", nil},
+		{"eqfield=Password", "eqfield:Password", nil},
+		{"required,dive,email", "required dive email", nil},
+		{"url", "url", nil},
+		{"-", "", nil},
+		{"required,alpha|numeric,startswith=a", "required", []string{"alpha|numeric", "startswith=a"}},
+		{"min=abc", "", []string{"min=abc"}},
+		{"required=true", "", []string{"required=true"}},
+		{"oneof=", "", []string{"oneof="}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			rules, untranslated := TranslateValidateTag(tt.tag)
+			if rules != tt.wantRules || !reflect.DeepEqual(untranslated, tt.wantUntranslated) {
+				t.Errorf("TranslateValidateTag() = %q, %q, want %q, %q", rules, untranslated, tt.wantRules, tt.wantUntranslated)
+			}
+		})
+	}
+}
+
+func TestWithValidateTags(t *testing.T) {
+	type legacy struct {
+		Name  string `validate:"required,min=3"`
+		Color string `validate:"oneof=red green" enforce:"enum:blue"`
+	}
+	v := New(WithValidateTags())
+	got := v.Validate(&legacy{Name: "ab", Color: "blue"})
+	if len(got) != 1 {
+		t.Errorf("Validate() = %q, want only the translated min to fail", got)
+	}
+	if got := Validate(&legacy{Name: "ab", Color: "blue"}); len(got) != 0 {
+		t.Errorf("Validate() without WithValidateTags = %q, want the validate tag ignored", got)
+	}
+
+	type untranslatable struct {
+		Name string `validate:"startswith=a"`
+	}
+	var schemaErr *SchemaError
+	if err := v.Check(&untranslatable{}); !errors.As(err, &schemaErr) || schemaErr.Field != "Name" {
+		t.Errorf("Check() = %v, want a SchemaError for Name", err)
+	}
+}
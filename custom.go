@@ -1,60 +1,20 @@
 package enforcer
 
-import (
-	"fmt"
-	"reflect"
-	"strconv"
-	"strings"
-)
+import "strings"
 
 type CustomEnforcements []map[string]func(string) string
 
-func CustomValidator(req interface{}, customEnforcements CustomEnforcements) []string {
-	errors := Validate(req)
-
-	v := reflect.ValueOf(req)
-	if v.Kind() == reflect.Ptr {
-		v = v.Elem()
-	}
-
-	t := v.Type()
-
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		enforceTag := field.Tag.Get("enforce")
-
-		if enforceTag != "" {
-			fieldValue := v.Field(i)
-			fieldString := ""
-			fieldType := fieldValue.Type()
-			switch fieldType.Kind() {
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-				fieldString = strconv.Itoa(int(fieldValue.Int()))
-			default:
-				fieldString = fieldValue.String()
-			}
-			enforceOpts := strings.Split(enforceTag, " ")
-
-			for _, opt := range enforceOpts {
-				if strings.HasPrefix(opt, "custom") {
-					enforcementNames := getCustomEnforcementNames(opt)
-					for _, enforcementName := range enforcementNames {
-						if enforcementFunc, ok := getCustomEnforcementFunc(customEnforcements, enforcementName); ok {
-							// There's probably a better way to do all this
-							err := enforcementFunc(fieldString)
-							if err != "" {
-								errors = append(errors, err)
-							}
-						} else {
-							errors = append(errors, fmt.Sprintf("Custom enforcement '%s' not found for field '%s'", enforcementName, field.Name))
-						}
-					}
-				}
-			}
-		}
-	}
+// CustomValidator validates like Validate and also runs the functions named by
+// `custom:` rules
+func CustomValidator(req interface{}, customEnforcements CustomEnforcements, opts ...Option) []string {
+	return defaultValidator.CustomValidator(req, customEnforcements, opts...)
+}
 
-	return errors
+// CustomValidator validates like Validate and also runs the given functions
+// for `custom:` rules, after the ones passed to WithCustomEnforcements
+func (v *Validator) CustomValidator(req interface{}, customEnforcements CustomEnforcements, opts ...Option) []string {
+	custom := append(CustomEnforcements{}, v.custom...)
+	return clientMessages(v.validate(req, opts, append(custom, customEnforcements...)))
 }
 
 func getCustomEnforcementFunc(
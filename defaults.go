@@ -0,0 +1,13 @@
+package enforcer
+
+import "github.com/rrojan/enforcer/enforcements"
+
+// DefaultProvider computes a default value for `default:@name` or `default:@name(arg)`
+type DefaultProvider = enforcements.DefaultProvider
+
+// RegisterDefaultProvider makes a provider available to `default:@<name>` tags.
+// `@uuid` and `@env(NAME)` are built in. Structs compiled before are compiled
+// again, and a nil provider panics.
+func RegisterDefaultProvider(name string, provider DefaultProvider) {
+	enforcements.RegisterDefaultProvider(name, provider)
+}
@@ -0,0 +1,109 @@
+package enforcer
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRegisterDefaultProviderRecompiles(t *testing.T) {
+	type account struct {
+		Plan string `enforce:"default:@test.recompile"`
+	}
+	var schemaErr *SchemaError
+	if err := Check(&account{}); !errors.As(err, &schemaErr) {
+		t.Fatalf("Check with an unregistered provider = %v, want a SchemaError", err)
+	}
+
+	RegisterDefaultProvider("test.recompile", func(ctx context.Context, arg string) (interface{}, error) {
+		return "free", nil
+	})
+	req := account{}
+	if err := Check(&req); err != nil {
+		t.Fatalf("Check after registering the provider = %v", err)
+	}
+	if req.Plan != "free" {
+		t.Errorf("Plan = %q, want the provided default", req.Plan)
+	}
+}
+
+func TestElementDefaults(t *testing.T) {
+	type item struct {
+		Qty int `json:"qty" enforce:"default:1 min:1"`
+	}
+	type cart struct {
+		Items []item          `json:"items"`
+		Refs  []*item         `json:"refs"`
+		ByKey map[string]item `json:"byKey"`
+		Fixed [1]item         `json:"fixed"`
+	}
+
+	req := cart{Items: []item{{}}, Refs: []*item{{}, nil}, ByKey: map[string]item{"a": {}}}
+	if err := Check(&req); err != nil {
+		t.Fatalf("Check = %v, want the defaults to satisfy min", err)
+	}
+	if req.Items[0].Qty != 1 || req.Refs[0].Qty != 1 || req.ByKey["a"].Qty != 1 || req.Fixed[0].Qty != 1 {
+		t.Errorf("defaults not applied to elements: %+v %+v %+v %+v", req.Items, req.Refs[0], req.ByKey, req.Fixed)
+	}
+
+	// A zero the client sent keeps its value and fails min
+	req = cart{Items: []item{{}, {}}}
+	err := Check(&req, WithPresentKeys([]string{"items", "items.0", "items.0.qty", "items.1"}))
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "Items[0].Qty" {
+		t.Fatalf("Check = %v, want min to fail on Items[0].Qty only", err)
+	}
+	if req.Items[1].Qty != 1 {
+		t.Errorf("Items[1].Qty = %d, want the default", req.Items[1].Qty)
+	}
+}
+
+func TestDefaultRuleConflicts(t *testing.T) {
+	type score struct {
+		Value float64 `enforce:"default:50 between:0,10"`
+	}
+	type plan struct {
+		Tier string `enforce:"enum:a,b default:c"`
+	}
+	type name struct {
+		Nick string `enforce:"default:ab min:3"`
+	}
+	type nested struct {
+		Plan plan
+	}
+	type unsettable struct {
+		Count int `enforce:"default:many"`
+	}
+	type valid struct {
+		Value float64 `enforce:"default:5 between:0,10"`
+		Tier  string  `enforce:"enum:a,b default:b"`
+	}
+
+	tests := []struct {
+		name     string
+		req      interface{}
+		wantRule string
+	}{
+		{"between", &score{}, "between"},
+		{"enum", &plan{}, "enum"},
+		{"min length", &name{}, "min"},
+		{"nested", &nested{}, "enum"},
+		{"unsettable", &unsettable{}, "default"},
+		{"valid", &valid{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Precompile(tt.req)
+			if tt.wantRule == "" {
+				if err != nil {
+					t.Errorf("Precompile() = %v, want nil", err)
+				}
+				return
+			}
+			var schemaErr *SchemaError
+			if !errors.As(err, &schemaErr) || schemaErr.Rule != tt.wantRule || schemaErr.Struct == "" || schemaErr.Field == "" {
+				t.Errorf("Precompile() = %#v, want a SchemaError naming the struct, field and rule '%s'", err, tt.wantRule)
+			}
+		})
+	}
+}
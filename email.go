@@ -0,0 +1,26 @@
+package enforcer
+
+import (
+	"io"
+
+	"github.com/rrojan/enforcer/enforcements"
+)
+
+// EmailResolver looks up mail exchangers for `email:mx`. *net.Resolver satisfies it.
+type EmailResolver = enforcements.EmailResolver
+
+// SetEmailResolver replaces the resolver used by `email:mx`, e.g. with a fake in tests
+func SetEmailResolver(r EmailResolver) {
+	enforcements.SetEmailResolver(r)
+}
+
+// SetDisposableDomains replaces the blocklist used by `email:noDisposable`
+func SetDisposableDomains(domains []string) {
+	enforcements.SetDisposableDomains(domains)
+}
+
+// LoadDisposableDomains replaces the `email:noDisposable` blocklist with one read
+// from r, one domain per line
+func LoadDisposableDomains(r io.Reader) error {
+	return enforcements.LoadDisposableDomains(r)
+}
@@ -0,0 +1,102 @@
+package enforcements
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// completedYears counts whole years from birth to now. The birth date is read
+// as a calendar date, and someone born on February 29th completes a year on
+// March 1st in non-leap years.
+func completedYears(birth, now time.Time) int {
+	by, bm, bd := birth.Date()
+	ny, nm, nd := now.Date()
+	age := ny - by
+	if nm < bm || (nm == bm && nd < bd) {
+		age--
+	}
+	return age
+}
+
+// toBirthDate reads a time.Time or a date string in `2006-01-02` or RFC 3339 form
+func toBirthDate(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, !v.IsZero()
+	case string:
+		for _, layout := range []string{"2006-01-02", time.RFC3339} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// handleAge computes the age for `minAge` and `maxAge`. The FieldError it
+// returns carries the computed age in Params["age"].
+func handleAge(value interface{}, fieldName, opt, rule string, now time.Time) (int, int, *FieldError) {
+	limit, err := strconv.Atoi(strings.TrimPrefix(opt, rule+":"))
+	if err != nil || limit < 0 {
+		return 0, 0, &FieldError{
+			Field:   fieldName,
+			Rule:    rule,
+			Message: fmt.Sprintf("Invalid %s value%s", rule, ForField(fieldName)),
+		}
+	}
+
+	birth, ok := toBirthDate(value)
+	if !ok {
+		return 0, 0, &FieldError{
+			Field:   fieldName,
+			Rule:    rule,
+			Message: fmt.Sprintf("%s must be a valid birth date", Subject(fieldName)),
+		}
+	}
+	age := completedYears(birth, now)
+	if age < 0 {
+		return 0, 0, &FieldError{
+			Field:   fieldName,
+			Rule:    rule,
+			Message: fmt.Sprintf("%s must not be in the future", Subject(fieldName)),
+			Params:  map[string]interface{}{"age": age},
+		}
+	}
+	return age, limit, nil
+}
+
+func HandleMinAge(value interface{}, fieldName, opt string, now time.Time) *FieldError {
+	age, min, err := handleAge(value, fieldName, opt, "minAge", now)
+	if err != nil {
+		return err
+	}
+
+	if age < min {
+		return &FieldError{
+			Field:   fieldName,
+			Rule:    "minAge",
+			Message: fmt.Sprintf("%s must be at least %d years old", Subject(fieldName), min),
+			Params:  map[string]interface{}{"age": age, "min": min},
+		}
+	}
+	return nil
+}
+
+func HandleMaxAge(value interface{}, fieldName, opt string, now time.Time) *FieldError {
+	age, max, err := handleAge(value, fieldName, opt, "maxAge", now)
+	if err != nil {
+		return err
+	}
+
+	if age > max {
+		return &FieldError{
+			Field:   fieldName,
+			Rule:    "maxAge",
+			Message: fmt.Sprintf("%s must be at most %d years old", Subject(fieldName), max),
+			Params:  map[string]interface{}{"age": age, "max": max},
+		}
+	}
+	return nil
+}
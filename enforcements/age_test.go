@@ -0,0 +1,72 @@
+package enforcements
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompletedYears(t *testing.T) {
+	leapBirthday := time.Date(2004, time.February, 29, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		birth, now time.Time
+		want       int
+	}{
+		{"day before birthday", time.Date(2000, time.June, 15, 0, 0, 0, 0, time.UTC), time.Date(2018, time.June, 14, 23, 0, 0, 0, time.UTC), 17},
+		{"on birthday", time.Date(2000, time.June, 15, 0, 0, 0, 0, time.UTC), time.Date(2018, time.June, 15, 0, 0, 0, 0, time.UTC), 18},
+		{"leap birthday on Feb 28 of a common year", leapBirthday, time.Date(2022, time.February, 28, 12, 0, 0, 0, time.UTC), 17},
+		{"leap birthday on Mar 1 of a common year", leapBirthday, time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC), 18},
+		{"leap birthday on Feb 28 of a leap year", leapBirthday, time.Date(2024, time.February, 28, 0, 0, 0, 0, time.UTC), 19},
+		{"leap birthday on Feb 29 of a leap year", leapBirthday, time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC), 20},
+		{"born today", leapBirthday, leapBirthday, 0},
+		{"born tomorrow", leapBirthday, leapBirthday.AddDate(0, 0, -1), -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := completedYears(tt.birth, tt.now); got != tt.want {
+				t.Errorf("completedYears(%v, %v) = %d, want %d", tt.birth, tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleMinAge(t *testing.T) {
+	now := time.Date(2022, time.February, 28, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   interface{}
+		opt     string
+		wantAge interface{}
+		wantErr bool
+	}{
+		{"2004-02-29", "minAge:18", 17, true},
+		{"2004-02-28", "minAge:18", nil, false},
+		{time.Date(2004, time.February, 29, 0, 0, 0, 0, time.UTC), "minAge:17", nil, false},
+		{"2023-01-01", "minAge:0", -1, true},
+		{"not a date", "minAge:18", nil, true},
+		{"2004-02-28", "minAge:eighteen", nil, true},
+	}
+	for _, tt := range tests {
+		err := HandleMinAge(tt.value, "Birthday", tt.opt, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("HandleMinAge(%v, %q) = %v, want error %v", tt.value, tt.opt, err, tt.wantErr)
+			continue
+		}
+		if err != nil && err.Params["age"] != tt.wantAge {
+			t.Errorf("HandleMinAge(%v, %q) age = %v, want %v", tt.value, tt.opt, err.Params["age"], tt.wantAge)
+		}
+	}
+}
+
+func TestHandleMaxAge(t *testing.T) {
+	now := time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)
+	err := HandleMaxAge("2004-02-29", "Birthday", "maxAge:17", now)
+	if err == nil {
+		t.Fatal("HandleMaxAge accepted an 18 year old with maxAge:17")
+	}
+	if err.Rule != "maxAge" || err.Params["age"] != 18 || err.Params["max"] != 17 {
+		t.Errorf("HandleMaxAge returned %+v", err)
+	}
+	if err := HandleMaxAge("2004-03-02", "Birthday", "maxAge:17", now); err != nil {
+		t.Errorf("HandleMaxAge rejected a 17 year old: %v", err)
+	}
+}
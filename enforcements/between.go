@@ -9,21 +9,21 @@ import (
 func HandleBetweenInt(fieldValue int64, fieldName, opt string) string {
 	rangeVals := strings.Split(strings.TrimPrefix(opt, "between:"), ",")
 	if len(rangeVals) != 2 {
-		return fmt.Sprintf("Invalid range values for field '%s'", fieldName)
+		return fmt.Sprintf("Invalid range values%s", ForField(fieldName))
 	}
 
 	min, err := strconv.Atoi(rangeVals[0])
 	if err != nil {
-		return fmt.Sprintf("Invalid range values for field '%s'", fieldName)
+		return fmt.Sprintf("Invalid range values%s", ForField(fieldName))
 	}
 
 	max, err := strconv.Atoi(rangeVals[1])
 	if err != nil {
-		return fmt.Sprintf("Invalid range values for field '%s'", fieldName)
+		return fmt.Sprintf("Invalid range values%s", ForField(fieldName))
 	}
 
 	if int(fieldValue) < min || int(fieldValue) > max {
-		return fmt.Sprintf("Field '%s' must be between %d and %d", fieldName, min, max)
+		return fmt.Sprintf("%s must be between %d and %d", Subject(fieldName), min, max)
 	}
 
 	return ""
@@ -32,21 +32,44 @@ func HandleBetweenInt(fieldValue int64, fieldName, opt string) string {
 func HandleBetweenStr(fieldValue, fieldName, opt string) string {
 	rangeVals := strings.Split(strings.TrimPrefix(opt, "between:"), ",")
 	if len(rangeVals) != 2 {
-		return fmt.Sprintf("Invalid range values for field '%s'", fieldName)
+		return fmt.Sprintf("Invalid range values%s", ForField(fieldName))
 	}
 
 	min, err := strconv.Atoi(rangeVals[0])
 	if err != nil {
-		return fmt.Sprintf("Invalid range values for field '%s'", fieldName)
+		return fmt.Sprintf("Invalid range values%s", ForField(fieldName))
 	}
 
 	max, err := strconv.Atoi(rangeVals[1])
 	if err != nil {
-		return fmt.Sprintf("Invalid range values for field '%s'", fieldName)
+		return fmt.Sprintf("Invalid range values%s", ForField(fieldName))
 	}
 
 	if len(fieldValue) < min || len(fieldValue) > max {
-		return fmt.Sprintf("Field '%s' must be between %s and %s characters", fieldName, rangeVals[0], rangeVals[1])
+		return fmt.Sprintf("%s must be between %s and %s characters", Subject(fieldName), rangeVals[0], rangeVals[1])
+	}
+
+	return ""
+}
+
+func HandleBetweenFloat(fieldValue float64, fieldName, opt string) string {
+	rangeVals := strings.Split(strings.TrimPrefix(opt, "between:"), ",")
+	if len(rangeVals) != 2 {
+		return fmt.Sprintf("Invalid range values%s", ForField(fieldName))
+	}
+
+	min, err := strconv.ParseFloat(rangeVals[0], 64)
+	if err != nil {
+		return fmt.Sprintf("Invalid range values%s", ForField(fieldName))
+	}
+
+	max, err := strconv.ParseFloat(rangeVals[1], 64)
+	if err != nil {
+		return fmt.Sprintf("Invalid range values%s", ForField(fieldName))
+	}
+
+	if fieldValue < min || fieldValue > max {
+		return fmt.Sprintf("%s must be between %s and %s", Subject(fieldName), rangeVals[0], rangeVals[1])
 	}
 
 	return ""
@@ -0,0 +1,273 @@
+package enforcements
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+var cronDayNames = map[string]int{
+	"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+}
+
+// cronSchedule is a parsed cron expression with one bit set per allowed value
+type cronSchedule struct {
+	seconds, minutes, hours, dom, months, dow uint64
+	// Like Vixie cron, a day matches either field when both are restricted
+	domRestricted, dowRestricted bool
+	// every is set for `@every <duration>` schedules
+	every time.Duration
+}
+
+// parseCron reads 5 field, 6 field (with leading seconds) and macro expressions
+func parseCron(expr string) (*cronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, "@every ")))
+		if err != nil || d < time.Second {
+			return nil, errors.New("@every needs a duration of at least 1s")
+		}
+		return &cronSchedule{every: d}, nil
+	}
+	if macro, ok := cronMacros[expr]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("expected 5 or 6 fields, got %d", len(fields))
+	}
+
+	s := &cronSchedule{}
+	var err error
+	if s.seconds, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("seconds: %w", err)
+	}
+	if s.minutes, err = parseCronField(fields[1], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("minutes: %w", err)
+	}
+	if s.hours, err = parseCronField(fields[2], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("hours: %w", err)
+	}
+	if s.dom, err = parseCronField(fields[3], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if s.months, err = parseCronField(fields[4], 1, 12, cronMonthNames); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	if s.dow, err = parseCronField(fields[5], 0, 7, cronDayNames); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+	// 7 is an alias for Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.domRestricted = fields[3] != "*" && fields[3] != "?"
+	s.dowRestricted = fields[5] != "*" && fields[5] != "?"
+	return s, nil
+}
+
+// parseCronField reads a comma separated list of values, ranges and steps
+// such as `*/15`, `1-5`, `MON-FRI` or `0,30`
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if before, after, found := strings.Cut(part, "/"); found {
+			n, err := strconv.Atoi(after)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step in '%s'", part)
+			}
+			rangePart, step = before, n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" && rangePart != "?" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = parseCronValue(from, names); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = parseCronValue(to, names); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("'%s' is outside %d-%d", part, min, max)
+		}
+		for i := lo; i <= hi; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(s string, names map[string]int) (int, error) {
+	if n, ok := names[strings.ToUpper(s)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s'", s)
+	}
+	return n, nil
+}
+
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	if s.months&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domRestricted && s.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// cronMonthDays is the longest each month gets, February 29th included
+var cronMonthDays = [13]int{0, 31, 29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+// runs reports whether the schedule ever runs. A restricted day of week always
+// matches some day, so only the days of month have to exist in an allowed
+// month, which `0 0 30 2 *` never does.
+func (s *cronSchedule) runs() bool {
+	if s.every > 0 || s.dowRestricted {
+		return true
+	}
+	for m := 1; m <= 12; m++ {
+		if s.months&(1<<uint(m)) == 0 {
+			continue
+		}
+		for d := 1; d <= cronMonthDays[m]; d++ {
+			if s.dom&(1<<uint(d)) != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cronIntervalYears spans every weekday and leap year combination of the calendar
+const cronIntervalYears = 28
+
+// minInterval returns the shortest gap between two runs. It walks the days of a
+// full calendar cycle in UTC, so DST transitions are not taken into account.
+// ok is false when the schedule never runs, e.g. `0 0 30 2 *`.
+func (s *cronSchedule) minInterval() (gap time.Duration, ok bool) {
+	if s.every > 0 {
+		return s.every, true
+	}
+
+	var secondsOfDay []int
+	for h := 0; h < 24; h++ {
+		if s.hours&(1<<uint(h)) == 0 {
+			continue
+		}
+		for m := 0; m < 60; m++ {
+			if s.minutes&(1<<uint(m)) == 0 {
+				continue
+			}
+			for sec := 0; sec < 60; sec++ {
+				if s.seconds&(1<<uint(sec)) != 0 {
+					secondsOfDay = append(secondsOfDay, h*3600+m*60+sec)
+				}
+			}
+		}
+	}
+
+	minGap := -1
+	for i := 1; i < len(secondsOfDay); i++ {
+		if d := secondsOfDay[i] - secondsOfDay[i-1]; minGap < 0 || d < minGap {
+			minGap = d
+		}
+	}
+
+	first, last := secondsOfDay[0], secondsOfDay[len(secondsOfDay)-1]
+	start := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(cronIntervalYears, 0, 0)
+	lastDay := -1
+	for day, t := 0, start; t.Before(end); day, t = day+1, t.AddDate(0, 0, 1) {
+		if !s.matchesDay(t) {
+			continue
+		}
+		if lastDay >= 0 {
+			if d := (day-lastDay)*86400 + first - last; minGap < 0 || d < minGap {
+				minGap = d
+			}
+		}
+		lastDay = day
+	}
+	if lastDay < 0 {
+		return 0, false
+	}
+	if minGap < 0 {
+		// A single run in the whole cycle, e.g. only on February 29th
+		minGap = cronIntervalYears * 365 * 86400
+	}
+	return time.Duration(minGap) * time.Second, true
+}
+
+// parseMinInterval reads the `minInterval=<duration>` option of `cron:`,
+// returning 0 without one
+func parseMinInterval(param string) (time.Duration, error) {
+	if param == "" {
+		return 0, nil
+	}
+	if !strings.HasPrefix(param, "minInterval=") {
+		return 0, fmt.Errorf("unknown option '%s'", param)
+	}
+	return time.ParseDuration(strings.TrimPrefix(param, "minInterval="))
+}
+
+// HandleCron validates a cron expression. `cron:minInterval=5m` also rejects
+// schedules that can run more often than the given duration.
+func HandleCron(fieldValue, fieldName, opt string) string {
+	param := strings.TrimPrefix(strings.TrimPrefix(opt, "cron"), ":")
+	minInterval, err := parseMinInterval(param)
+	if err != nil {
+		return fmt.Sprintf("Invalid cron option '%s'%s", param, ForField(fieldName))
+	}
+
+	schedule, err := parseCron(fieldValue)
+	if err != nil {
+		return fmt.Sprintf("%s must be a valid cron expression: %s", Subject(fieldName), err)
+	}
+	if !schedule.runs() {
+		return fmt.Sprintf("%s is a cron expression that never runs", Subject(fieldName))
+	}
+	// Finding the shortest gap walks a whole calendar cycle, so it is only done
+	// when a minimum is set
+	if minInterval > 0 {
+		if gap, ok := schedule.minInterval(); ok && gap < minInterval {
+			return fmt.Sprintf("%s must not run more often than every %s", Subject(fieldName), minInterval)
+		}
+	}
+	return ""
+}
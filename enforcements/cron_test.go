@@ -0,0 +1,76 @@
+package enforcements
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHandleCron(t *testing.T) {
+	tests := []struct {
+		value   string
+		opt     string
+		wantErr bool
+	}{
+		{"*/5 * * * *", "cron", false},
+		{"0 9 * * MON-FRI", "cron", false},
+		{"30 0 0 1 1 *", "cron", false},
+		{"0 0 29 2 *", "cron", false},
+		{"0 0 30 2 MON", "cron", false},
+		{"@daily", "cron", false},
+		{"@every 90s", "cron", false},
+		{"0 0 30 2 *", "cron", true},
+		{"0 0 31 4,6,9,11 *", "cron", true},
+		{"0 0 31 2-4 *", "cron", false},
+		{"60 * * * *", "cron", true},
+		{"* * * *", "cron", true},
+		{"*/0 * * * *", "cron", true},
+		{"5-1 * * * *", "cron", true},
+		{"@every 10ms", "cron", true},
+		{"@sometimes", "cron", true},
+		{"* * * * *", "cron:minInterval=5m", true},
+		{"*/5 * * * *", "cron:minInterval=5m", false},
+		{"0,3 * * * *", "cron:minInterval=5m", true},
+		{"0,57 * * * *", "cron:minInterval=5m", true},
+		{"0 23,0 * * *", "cron:minInterval=2h", true},
+		{"0 0 * * *", "cron:minInterval=24h", false},
+		{"0 23 * * *", "cron:minInterval=25h", true},
+		{"@hourly", "cron:minInterval=1h", false},
+		{"@every 30s", "cron:minInterval=1m", true},
+		{"0 0 29 2 *", "cron:minInterval=8760h", false},
+		{"* * * * *", "cron:minInterval=soon", true},
+		{"* * * * *", "cron:maxInterval=5m", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.opt+" "+tt.value, func(t *testing.T) {
+			if got := HandleCron(tt.value, "Schedule", tt.opt); (got != "") != tt.wantErr {
+				t.Errorf("HandleCron(%q, %q) = %q, want error %v", tt.value, tt.opt, got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCronMinInterval(t *testing.T) {
+	tests := []struct {
+		expr string
+		want time.Duration
+	}{
+		{"* * * * * *", time.Second},
+		{"*/15 * * * *", 15 * time.Minute},
+		{"0 22,2 * * *", 4 * time.Hour},
+		{"0 9 * * MON,WED", 48 * time.Hour},
+		{"0 0 1 * *", 28 * 24 * time.Hour},
+		{"@every 1h30m", 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := parseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("parseCron(%q) failed: %v", tt.expr, err)
+			}
+			got, ok := s.minInterval()
+			if !ok || got != tt.want {
+				t.Errorf("minInterval(%q) = %v, %v, want %v", tt.expr, got, ok, tt.want)
+			}
+		})
+	}
+}
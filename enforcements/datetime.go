@@ -0,0 +1,109 @@
+package enforcements
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// namedLayouts are the formats accepted by name in `datetime:<format>`
+var namedLayouts = map[string]string{
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC822":      time.RFC822,
+	"date":        "2006-01-02",
+	"time":        "15:04:05",
+	"datetime":    "2006-01-02 15:04:05",
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// datetimeBound is a `before=` or `after=` option of `datetime:`
+type datetimeBound struct {
+	key, value string
+}
+
+// datetimeParams is the parsed parameter of `datetime:`
+type datetimeParams struct {
+	// format is the format as written in the tag, for messages
+	format string
+	layout string
+	bounds []datetimeBound
+	into   string
+}
+
+// parseDatetimeParams reads `<format>[,after=<time>][,before=<time>][,into=<Field>]`,
+// checking every option before any value is parsed
+func parseDatetimeParams(param string) (datetimeParams, error) {
+	params := strings.Split(param, ",")
+	p := datetimeParams{format: strings.ReplaceAll(params[0], ";", ":")}
+	p.layout = namedLayouts[params[0]]
+	if p.layout == "" {
+		p.layout = p.format
+	}
+	if p.layout == "" {
+		return p, errors.New("a format is required")
+	}
+
+	for _, option := range params[1:] {
+		key, val, _ := strings.Cut(option, "=")
+		switch key {
+		case "before", "after":
+			if _, err := parseTimeValue(val, time.Now()); err != nil {
+				return p, fmt.Errorf("invalid %s value: %w", key, err)
+			}
+			p.bounds = append(p.bounds, datetimeBound{key: key, value: val})
+		case "into":
+			if val == "" {
+				return p, errors.New("into needs a field name")
+			}
+			p.into = val
+		default:
+			return p, fmt.Errorf("unknown option '%s'", option)
+		}
+	}
+	return p, nil
+}
+
+// HandleDatetime checks that a string parses with a named format or a Go layout,
+// written as `datetime:<format>[,after=<time>][,before=<time>][,into=<Field>]`.
+// Layouts use `;` in place of `:`. Bounds take the same values as `before` and
+// `after`, and `into` stores the parsed time in a sibling time.Time field.
+func HandleDatetime(fieldValue, fieldName, opt string, sibling SiblingLookup, now time.Time) string {
+	p, err := parseDatetimeParams(strings.TrimPrefix(opt, "datetime:"))
+	if err != nil {
+		return fmt.Sprintf("Invalid datetime enforcement%s: %s", ForField(fieldName), err)
+	}
+	var target reflect.Value
+	if p.into != "" {
+		var ok bool
+		if sibling != nil {
+			target, ok = sibling(p.into)
+		}
+		if !ok || target.Type() != timeType || !target.CanSet() {
+			return fmt.Sprintf("Invalid datetime target '%s'%s", p.into, ForField(fieldName))
+		}
+	}
+
+	t, err := time.ParseInLocation(p.layout, fieldValue, now.Location())
+	if err != nil {
+		return fmt.Sprintf("%s must be a date/time in the format %s", Subject(fieldName), p.format)
+	}
+	for _, bound := range p.bounds {
+		check := HandleAfter
+		if bound.key == "before" {
+			check = HandleBefore
+		}
+		if err := check(t, fieldName, bound.key+":"+bound.value, now); err != "" {
+			return err
+		}
+	}
+	if target.IsValid() {
+		target.Set(reflect.ValueOf(t))
+	}
+	return ""
+}
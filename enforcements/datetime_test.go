@@ -0,0 +1,68 @@
+package enforcements
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleDatetime(t *testing.T) {
+	now := time.Date(2023, time.June, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		opt     string
+		wantErr string
+	}{
+		{"2023-06-01", "datetime:date", ""},
+		{"2023-6-1", "datetime:date", "must be a date/time in the format date"},
+		{"2023-06-01T10:00:00Z", "datetime:RFC3339", ""},
+		{"10:30:00", "datetime:time", ""},
+		{"2023-06-01 10:30:00", "datetime:datetime", ""},
+		{"Thu, 01 Jun 2023 10:30:00 UTC", "datetime:RFC1123", ""},
+		{"01/06/2023", "datetime:02/01/2006", ""},
+		{"10:30", "datetime:15;04", ""},
+		{"2023-06-01", "datetime:date,after=timeNow-1_year", ""},
+		{"2021-06-01", "datetime:date,after=timeNow-1_year", "must be after"},
+		{"2023-07-01", "datetime:date,after=timeNow-1_year,before=timeNow", "must be before"},
+		{"2023-06-01", "datetime:", "Invalid datetime enforcement"},
+		// Mistakes in the options are reported whatever the value
+		{"2023-06-01", "datetime:date,bogus=1", "Invalid datetime enforcement"},
+		{"not a date", "datetime:date,bogus=1", "Invalid datetime enforcement"},
+		{"2023-06-01", "datetime:date,after", "Invalid datetime enforcement"},
+		{"not a date", "datetime:date,after=yesterday", "Invalid datetime enforcement"},
+		{"not a date", "datetime:date,into=", "Invalid datetime enforcement"},
+		{"not a date", "datetime:date,into=At", "Invalid datetime target 'At'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.opt+" "+tt.value, func(t *testing.T) {
+			got := HandleDatetime(tt.value, "Day", tt.opt, nil, now)
+			if (got == "") != (tt.wantErr == "") || !strings.Contains(got, tt.wantErr) {
+				t.Errorf("HandleDatetime(%q, %q) = %q, want %q", tt.value, tt.opt, got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandleDatetimeInto(t *testing.T) {
+	req := struct {
+		At   time.Time
+		Name string
+	}{}
+	rv := reflect.ValueOf(&req).Elem()
+	sibling := func(name string) (reflect.Value, bool) {
+		f := rv.FieldByName(name)
+		return f, f.IsValid()
+	}
+	now := time.Date(2023, time.June, 15, 12, 0, 0, 0, time.UTC)
+
+	if got := HandleDatetime("2023-06-01", "Day", "datetime:date,into=At", sibling, now); got != "" {
+		t.Fatalf("HandleDatetime = %q", got)
+	}
+	if want := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC); !req.At.Equal(want) {
+		t.Errorf("into stored %v, want %v", req.At, want)
+	}
+	if got := HandleDatetime("2023-06-01", "Day", "datetime:date,into=Name", sibling, now); !strings.HasPrefix(got, "Invalid datetime target") {
+		t.Errorf("into a string field = %q, want an invalid target", got)
+	}
+}
@@ -1,6 +1,8 @@
 package enforcements
 
 import (
+	"encoding"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
@@ -9,7 +11,18 @@ import (
 	"time"
 )
 
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
 func ApplyDefaults(v interface{}) error {
+	return ApplyDefaultsWith(v, Settings{})
+}
+
+// ApplyDefaultsWith applies defaults using the clock, location and context in
+// settings. When settings has PresentKeys, fields whose JSON key was sent keep
+// their value even if it is zero. Defaults that can't be set are reported as a
+// *SchemaError, while a failing provider such as an unset `@env(REGION)` is a
+// plain error naming the field.
+func ApplyDefaultsWith(v interface{}, settings Settings) error {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Ptr || rv.IsNil() {
 		return fmt.Errorf("pointer to struct expected, got %T", v)
@@ -22,146 +35,310 @@ func ApplyDefaults(v interface{}) error {
 		return fmt.Errorf("pointer to struct expected, got pointer to %T", v)
 	}
 
+	return applyDefaults(rv, settings, fieldLocation{}, nil)
+}
+
+// ApplyDefaultsAndProhibits applies defaults like ApplyDefaultsWith and also
+// returns a FieldError for every prohibited field the client sent in the
+// `report` or `both` mode
+func ApplyDefaultsAndProhibits(v interface{}, settings Settings) ([]*FieldError, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("pointer to struct expected, got %T", v)
+	}
+
+	var reports []*FieldError
+	err := applyDefaults(rv.Elem(), settings, fieldLocation{}, &reports)
+	return reports, err
+}
+
+// fieldLocation is where a struct sits below the top level one
+type fieldLocation struct {
+	// key is the dotted JSON key, path the resolved names and pointer the
+	// JSON Pointer
+	key, path, pointer string
+}
+
+// at returns the location of an element of a collection, such as `items[2]`
+func (l fieldLocation) at(index string) fieldLocation {
+	return fieldLocation{
+		key:     JoinKey(l.key, index),
+		path:    l.path + "[" + index + "]",
+		pointer: JoinPointer(l.pointer, index),
+	}
+}
+
+// applyDefaults fills the defaults of a struct found at loc. Prohibited fields
+// are collected in reports when it is not nil.
+func applyDefaults(rv reflect.Value, settings Settings, loc fieldLocation, reports *[]*FieldError) error {
 	// Iterate over the fields of the struct
 	for i := 0; i < rv.NumField(); i++ {
 		fieldValue := rv.Field(i)
 		fieldType := rv.Type().Field(i)
 
-		// Check if the field has the enforce tag
-		tagValue := fieldType.Tag.Get("enforce")
-
-		if strings.Contains(tagValue, "prohibit") {
-			// If we are using prohibit with this field, reset the value
-			// to whatever the Zero value of that type is as a default
-			fieldValue.Set(reflect.Zero(fieldType.Type))
+		// Untagged embedded structs are flattened into their parent by encoding/json
+		fieldLoc := fieldLocation{
+			key:     JoinKey(loc.key, JSONName(fieldType)),
+			path:    JoinPath(loc.path, settings.NameOf(fieldType)),
+			pointer: JoinPointer(loc.pointer, JSONName(fieldType)),
+		}
+		if fieldType.Anonymous && fieldType.Tag.Get("json") == "" {
+			fieldLoc = loc
 		}
 
-		if tagValue == "" || !strings.Contains(tagValue, "default:") {
+		// Unexported fields can't be set, but like encoding/json the promoted
+		// fields of an unexported embedded struct can
+		if !fieldValue.CanSet() {
+			if nested, ok := nestedStruct(fieldValue); ok && fieldType.Anonymous {
+				if err := applyDefaults(nested, settings, fieldLoc, reports); err != nil {
+					return err
+				}
+			}
 			continue
 		}
 
-		// Check if the field is empty (zero value)
-		switch fieldValue.Kind() {
-		case reflect.String:
-			if fieldValue.String() == "" {
-				defaultValue := getDefaultValue(tagValue)
-				// Set the default value for the field
-				fieldValue.SetString(defaultValue)
+		// Check if the field has the enforce tag
+		tagValue := settings.Tag(fieldType)
+
+		mode, prohibited, err := prohibitMode(tagValue, settings)
+		if err != nil {
+			return &SchemaError{Struct: rv.Type().Name(), Field: fieldType.Name, Rule: "prohibit", Message: err.Error()}
+		}
+		stripped := false
+		if prohibited && sent(settings, fieldLoc.key, fieldValue) {
+			if mode != ProhibitStrip && reports != nil {
+				*reports = append(*reports, prohibitedError(Label(fieldType), fieldLoc))
 			}
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			if fieldValue.Int() == 0 {
-				defaultValue := getDefaultValue(tagValue)
-				// Convert the default value to the appropriate int type
-				defaultIntValue, err := strconv.ParseInt(defaultValue, 10, 64)
-				if err != nil {
-					return fmt.Errorf("failed to convert default value to int: %w", err)
+			if mode != ProhibitReport {
+				// Reset the value to whatever the Zero value of that type is, so
+				// a default can take over
+				fieldValue.Set(reflect.Zero(fieldType.Type))
+				stripped = true
+				if settings.OnStrip != nil {
+					path := fieldLoc.key
+					if path == "" {
+						path = fieldType.Name
+					}
+					settings.OnStrip(settings.CallContext(), path)
 				}
-				// Set the default value for the field
-				fieldValue.SetInt(defaultIntValue)
 			}
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			if fieldValue.Uint() == 0 {
-				defaultValue := getDefaultValue(tagValue)
-				// Convert the default value to the appropriate uint type
-				defaultUintValue, err := strconv.ParseUint(defaultValue, 10, 64)
-				if err != nil {
-					return fmt.Errorf("failed to convert default value to uint: %w", err)
+		}
+
+		// Only fields that were not provided take their default. A nil pointer is
+		// not provided while a pointer to 0 is.
+		defaultValue, hasDefault := DefaultValue(tagValue)
+		if hasDefault && fieldValue.IsZero() && (stripped || !sent(settings, fieldLoc.key, fieldValue)) {
+			if err := SetDefault(fieldValue, defaultValue, settings); err != nil {
+				var failed *providerError
+				if errors.As(err, &failed) {
+					return fmt.Errorf("enforcer: default of %s.%s: %w", rv.Type().Name(), fieldType.Name, failed.err)
 				}
-				// Set the default value for the field
-				fieldValue.SetUint(defaultUintValue)
+				return &SchemaError{Struct: rv.Type().Name(), Field: fieldType.Name, Rule: "default", Message: err.Error()}
+			}
+		}
+
+		// Nested structs carry their own defaults, also as the elements of a
+		// collection
+		if nested, ok := nestedStruct(fieldValue); ok {
+			if err := applyDefaults(nested, settings, fieldLoc, reports); err != nil {
+				return err
 			}
-		case reflect.Float32, reflect.Float64:
-			if fieldValue.Float() == 0.0 {
-				defaultValue := getDefaultValue(tagValue)
-				// Convert the default value to the appropriate float type
-				defaultFloatValue, err := strconv.ParseFloat(defaultValue, 64)
-				if err != nil {
-					return fmt.Errorf("failed to convert default value to float: %w", err)
+			continue
+		}
+		err = eachElement(fieldValue, func(elem reflect.Value, index string) error {
+			return applyDefaults(elem, settings, fieldLoc.at(index), reports)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// eachElement calls fn with every struct held in a slice, array or map, along
+// with its index or key. Structs held by value in a map can't be changed in
+// place, so fn gets a copy that is stored back.
+func eachElement(collection reflect.Value, fn func(elem reflect.Value, index string) error) error {
+	if collection.Kind() == reflect.Ptr {
+		if collection.IsNil() {
+			return nil
+		}
+		collection = collection.Elem()
+	}
+
+	switch collection.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < collection.Len(); i++ {
+			if elem, ok := nestedStruct(collection.Index(i)); ok {
+				if err := fn(elem, strconv.Itoa(i)); err != nil {
+					return err
 				}
-				// Set the default value for the field
-				fieldValue.SetFloat(defaultFloatValue)
 			}
-		case reflect.Struct:
-			if fieldValue.Type() == reflect.TypeOf(time.Time{}) {
-				if fieldValue.Interface().(time.Time).IsZero() {
-					defaultValue := getDefaultValue(tagValue)
-					defaultValue = strings.ReplaceAll(defaultValue, ";", ":")
-					defaultTime := time.Time{}
-					var err error
-					if strings.Contains(defaultValue, "timeNow") {
-						defaultTime = time.Now()
-						shiftStr := strings.ReplaceAll(defaultValue, "timeNow", "")
-						shiftStr = strings.ReplaceAll(shiftStr, "+", "")
-						shiftStr = strings.ReplaceAll(shiftStr, "-", "")
-
-						if shiftStr != "" {
-							timeShift, err := getTimeSetValue(shiftStr)
-							if err != nil {
-								return err
-							}
-							if strings.Contains(defaultValue, "+") {
-								defaultTime = defaultTime.Add(timeShift)
-							} else if strings.Contains(defaultValue, "-") {
-								defaultTime = defaultTime.Add(time.Duration(-1) * timeShift)
-							}
-						}
-					} else {
-						defaultTime, err = time.Parse("2006-01-02 15:04:05 -07:00", defaultValue)
-						if err != nil {
-							return fmt.Errorf("failed to convert default value to time: %w", err)
-						}
+		}
+	case reflect.Map:
+		for _, key := range collection.MapKeys() {
+			elem := collection.MapIndex(key)
+			if elem.Kind() == reflect.Ptr {
+				if nested, ok := nestedStruct(elem); ok {
+					if err := fn(nested, fmt.Sprint(key)); err != nil {
+						return err
 					}
-					// Set the default value for the field
-					fieldValue.Set(reflect.ValueOf(defaultTime))
 				}
+				continue
+			}
+			if !IsNestedStruct(elem.Type()) {
+				continue
 			}
+			copied := reflect.New(elem.Type()).Elem()
+			copied.Set(elem)
+			if err := fn(copied, fmt.Sprint(key)); err != nil {
+				return err
+			}
+			collection.SetMapIndex(key, copied)
 		}
 	}
-
 	return nil
 }
 
-func getTimeSetValue(t string) (time.Duration, error) {
-	values := strings.Split(t, "_")
-	duration, err := strconv.Atoi(values[0])
-	if err != nil {
-		return time.Second, errors.New("error parsing time shift")
+// sent reports whether a field holds input that defaults and prohibits must
+// respect. Without PresentKeys that is any value but the zero value, where a
+// pointer to 0 is not zero.
+func sent(settings Settings, key string, fieldValue reflect.Value) bool {
+	if settings.PresentKeys != nil {
+		return settings.Provided(key, fieldValue)
 	}
-	shiftMap := map[string]time.Duration {
-		"year": 365 * 24 * time.Hour,
-		"years": 365 * 24 * time.Hour,
-		"month": 30 * 24 * time.Hour,
-		"months": 30 * 24 * time.Hour,
-		"day": 24 * time.Hour,
-		"days": 24 * time.Hour,
-		"hour": time.Hour,
-		"hours": time.Hour,	
-		"minute": time.Minute,	
-		"minutes": time.Minute,	
-		"second": time.Second,	
-		"seconds": time.Second,	
+	return !fieldValue.IsZero()
+}
+
+// SetDefault converts the default value from the tag into the field's type.
+// Values starting with `@` come from a registered DefaultProvider, and `@@`
+// escapes a literal leading `@`.
+func SetDefault(fieldValue reflect.Value, defaultValue string, settings Settings) error {
+	if strings.HasPrefix(defaultValue, "@@") {
+		return setLiteralDefault(fieldValue, defaultValue[1:], settings)
 	}
-	shift, exists := shiftMap[values[1]]
-	if !exists {
-		return time.Second, errors.New("invalid time unit")
+	if strings.HasPrefix(defaultValue, "@") {
+		return provideDefault(fieldValue, defaultValue, settings)
 	}
+	return setLiteralDefault(fieldValue, defaultValue, settings)
+}
 
-	return time.Duration(duration) * shift, nil
+// IsProvidedDefault reports whether a default value comes from a provider and
+// is only known when defaults are applied
+func IsProvidedDefault(defaultValue string) bool {
+	return strings.HasPrefix(defaultValue, "@") && !strings.HasPrefix(defaultValue, "@@")
 }
 
-func getDefaultValue(tagValue string) string {
-	defaultValue := ""
-	// if strings.Contains(tagValue, "'") {
-	// 	re := regexp.MustCompile(`'([^']*)'`)
-	// 	match := re.FindStringSubmatch(tagValue)
-
-	// 	if len(match) >= 2 {
-	// 		result := match[1]
-	// 		fmt.Println(result)
-	// 	}
-	// }
-	defaultValue = strings.Split(tagValue, ":")[1]
-	// return strings.TrimSpace(defaultValue)
-	return strings.Split(defaultValue, " ")[0]
-	// TODO: this can mess things up but we will do this for the meanwhile
-}
\ No newline at end of file
+func setLiteralDefault(fieldValue reflect.Value, defaultValue string, settings Settings) error {
+	fieldType := fieldValue.Type()
+
+	switch {
+	case fieldType == timeType:
+		defaultTime, err := parseTimeValue(defaultValue, settings.Now())
+		if err != nil {
+			return fmt.Errorf("failed to convert default value to time: %w", err)
+		}
+		// Set the default value for the field
+		fieldValue.Set(reflect.ValueOf(defaultTime))
+		return nil
+	case fieldType == DurationType:
+		// Durations use Go duration syntax like 30s or 1h30m
+		defaultDuration, err := time.ParseDuration(defaultValue)
+		if err != nil {
+			return fmt.Errorf("failed to convert default value to duration: %w", err)
+		}
+		fieldValue.SetInt(int64(defaultDuration))
+		return nil
+	case reflect.PtrTo(fieldType).Implements(textUnmarshalerType):
+		// Custom types such as net.IP or enums decode themselves
+		if err := fieldValue.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(defaultValue)); err != nil {
+			return fmt.Errorf("failed to convert default value to %s: %w", fieldType, err)
+		}
+		return nil
+	}
+
+	switch fieldValue.Kind() {
+	case reflect.String:
+		// Set the default value for the field
+		fieldValue.SetString(defaultValue)
+	case reflect.Bool:
+		defaultBoolValue, err := strconv.ParseBool(defaultValue)
+		if err != nil {
+			return fmt.Errorf("failed to convert default value to bool: %w", err)
+		}
+		fieldValue.SetBool(defaultBoolValue)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// Convert the default value to the appropriate int type
+		defaultIntValue, err := strconv.ParseInt(defaultValue, 10, fieldType.Bits())
+		if err != nil {
+			return fmt.Errorf("failed to convert default value to int: %w", err)
+		}
+		// Set the default value for the field
+		fieldValue.SetInt(defaultIntValue)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// Convert the default value to the appropriate uint type
+		defaultUintValue, err := strconv.ParseUint(defaultValue, 10, fieldType.Bits())
+		if err != nil {
+			return fmt.Errorf("failed to convert default value to uint: %w", err)
+		}
+		// Set the default value for the field
+		fieldValue.SetUint(defaultUintValue)
+	case reflect.Float32, reflect.Float64:
+		// Convert the default value to the appropriate float type
+		defaultFloatValue, err := strconv.ParseFloat(defaultValue, fieldType.Bits())
+		if err != nil {
+			return fmt.Errorf("failed to convert default value to float: %w", err)
+		}
+		// Set the default value for the field
+		fieldValue.SetFloat(defaultFloatValue)
+	case reflect.Ptr:
+		// Allocate the pointer and apply the default to what it points to
+		elem := reflect.New(fieldType.Elem())
+		if err := setLiteralDefault(elem.Elem(), defaultValue, settings); err != nil {
+			return err
+		}
+		fieldValue.Set(elem)
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.Struct:
+		// Composite defaults are JSON literals like ["a","b"] or {"key":1}
+		if err := json.Unmarshal([]byte(defaultValue), fieldValue.Addr().Interface()); err != nil {
+			return fmt.Errorf("failed to convert default value to %s: %w", fieldType, err)
+		}
+	default:
+		return fmt.Errorf("default values are not supported for %s", fieldType)
+	}
+
+	return nil
+}
+
+// nestedStruct returns the struct a field holds directly or through a non-nil
+// pointer, leaving out types like time.Time that are values in their own right
+func nestedStruct(fieldValue reflect.Value) (reflect.Value, bool) {
+	if fieldValue.Kind() == reflect.Ptr {
+		if fieldValue.IsNil() {
+			return reflect.Value{}, false
+		}
+		fieldValue = fieldValue.Elem()
+	}
+	if fieldValue.Kind() != reflect.Struct || !IsNestedStruct(fieldValue.Type()) {
+		return reflect.Value{}, false
+	}
+	return fieldValue, true
+}
+
+// IsNestedStruct reports whether a struct type holds fields of its own rather
+// than being a value like time.Time or a type that decodes itself from text
+func IsNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// DefaultValue returns the value of the `default:` option of a tag. Values
+// can't contain spaces since options are separated by them.
+func DefaultValue(tagValue string) (string, bool) {
+	for _, opt := range strings.Split(tagValue, " ") {
+		if strings.HasPrefix(opt, "default:") {
+			return strings.TrimPrefix(opt, "default:"), true
+		}
+	}
+	return "", false
+}
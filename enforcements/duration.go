@@ -0,0 +1,76 @@
+package enforcements
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DurationType is matched before the int64 kind so that time.Duration fields
+// are read with Go duration syntax like `30s` or `1h30m` instead of nanoseconds
+var DurationType = reflect.TypeOf(time.Duration(0))
+
+func HandleMinDuration(fieldValue time.Duration, fieldName, opt string) string {
+	min, err := time.ParseDuration(strings.TrimPrefix(opt, "min:"))
+	if err != nil {
+		return fmt.Sprintf("Invalid minimum value%s", ForField(fieldName))
+	}
+
+	if fieldValue < min {
+		return fmt.Sprintf("%s must be at least %s", Subject(fieldName), min)
+	}
+	return ""
+}
+
+func HandleMaxDuration(fieldValue time.Duration, fieldName, opt string) string {
+	max, err := time.ParseDuration(strings.TrimPrefix(opt, "max:"))
+	if err != nil {
+		return fmt.Sprintf("Invalid max value%s", ForField(fieldName))
+	}
+
+	if fieldValue > max {
+		return fmt.Sprintf("%s must be at most %s", Subject(fieldName), max)
+	}
+	return ""
+}
+
+func HandleBetweenDuration(fieldValue time.Duration, fieldName, opt string) string {
+	rangeVals := strings.Split(strings.TrimPrefix(opt, "between:"), ",")
+	if len(rangeVals) != 2 {
+		return fmt.Sprintf("Invalid range values%s", ForField(fieldName))
+	}
+
+	min, err := time.ParseDuration(rangeVals[0])
+	if err != nil {
+		return fmt.Sprintf("Invalid range values%s", ForField(fieldName))
+	}
+
+	max, err := time.ParseDuration(rangeVals[1])
+	if err != nil {
+		return fmt.Sprintf("Invalid range values%s", ForField(fieldName))
+	}
+
+	if fieldValue < min || fieldValue > max {
+		return fmt.Sprintf("%s must be between %s and %s", Subject(fieldName), min, max)
+	}
+	return ""
+}
+
+func HandleEnumDuration(fieldValue time.Duration, fieldName, opt string) string {
+	enumValues := strings.Split(strings.TrimPrefix(opt, "enum:"), ",")
+	for _, enumStr := range enumValues {
+		enum, err := time.ParseDuration(enumStr)
+		if err != nil {
+			return fmt.Sprintf("Invalid enum value '%s'%s", enumStr, ForField(fieldName))
+		}
+		if fieldValue == enum {
+			return "" // Value is in the enum, no error
+		}
+	}
+
+	return fmt.Sprintf(
+		"%s does not match any enum values: %s",
+		Subject(fieldName), strings.Join(enumValues, ", "),
+	)
+}
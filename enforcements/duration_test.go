@@ -0,0 +1,62 @@
+package enforcements
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDurationEnforcements(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(time.Duration, string, string) string
+		value   time.Duration
+		opt     string
+		valid   bool
+	}{
+		{"min", HandleMinDuration, time.Minute, "min:30s", true},
+		{"min equal", HandleMinDuration, 30 * time.Second, "min:30s", true},
+		{"below min", HandleMinDuration, time.Second, "min:30s", false},
+		{"max", HandleMaxDuration, 90 * time.Minute, "max:1h30m", true},
+		{"above max", HandleMaxDuration, 2 * time.Hour, "max:1h30m", false},
+		{"between", HandleBetweenDuration, time.Minute, "between:30s,2m", true},
+		{"outside between", HandleBetweenDuration, 3 * time.Minute, "between:30s,2m", false},
+		{"enum", HandleEnumDuration, 5 * time.Minute, "enum:1m,5m,15m", true},
+		{"enum other unit", HandleEnumDuration, time.Hour, "enum:30m,60m", true},
+		{"not in enum", HandleEnumDuration, 2 * time.Minute, "enum:1m,5m,15m", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.handler(tt.value, "Timeout", tt.opt); (got == "") != tt.valid {
+				t.Errorf("got %q, want valid %v", got, tt.valid)
+			}
+		})
+	}
+}
+
+func TestDurationParams(t *testing.T) {
+	durationType := reflect.TypeOf(time.Duration(0))
+	tests := []struct {
+		rule, param string
+		valid       bool
+	}{
+		{"min", "30s", true},
+		{"max", "1h30m", true},
+		{"between", "30s,2m", true},
+		{"enum", "1m,5m", true},
+		// Durations need a unit, a bare number would be read as nanoseconds
+		{"min", "30", false},
+		{"between", "30s,2", false},
+		{"enum", "1m,five", false},
+		{"max", "1.5", false},
+	}
+	for _, tt := range tests {
+		err := ParseParam(tt.rule, tt.param)
+		if err == nil {
+			err = CheckParamFor(tt.rule, tt.param, durationType, nil)
+		}
+		if (err == nil) != tt.valid {
+			t.Errorf("%s:%s on a time.Duration = %v, want valid %v", tt.rule, tt.param, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package enforcements
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Length limits from RFC 5321 section 4.5.3.1
+const (
+	maxEmailLength  = 254
+	maxLocalLength  = 64
+	maxDomainLength = 253
+	maxLabelLength  = 63
+)
+
+// EmailResolver looks up the mail exchangers and hosts of a domain for
+// `email:mx`. *net.Resolver satisfies it, and tests can swap in a fake.
+type EmailResolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// emailLookupTimeout bounds the DNS lookups made by `email:mx` when the
+// context has no deadline of its own
+const emailLookupTimeout = 5 * time.Second
+
+var (
+	emailMu           sync.RWMutex
+	emailResolver     EmailResolver = net.DefaultResolver
+	disposableDomains               = map[string]bool{}
+)
+
+// SetEmailResolver replaces the resolver used by `email:mx`
+func SetEmailResolver(r EmailResolver) {
+	emailMu.Lock()
+	defer emailMu.Unlock()
+	emailResolver = r
+}
+
+// SetDisposableDomains replaces the blocklist used by `email:noDisposable`
+func SetDisposableDomains(domains []string) {
+	blocklist := make(map[string]bool, len(domains))
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d != "" {
+			blocklist[d] = true
+		}
+	}
+
+	emailMu.Lock()
+	defer emailMu.Unlock()
+	disposableDomains = blocklist
+}
+
+// LoadDisposableDomains reads a blocklist with one domain per line, ignoring
+// blank lines and `#` comments, and replaces the current one with it
+func LoadDisposableDomains(r io.Reader) error {
+	var domains []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains = append(domains, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read disposable domains: %w", err)
+	}
+	SetDisposableDomains(domains)
+	return nil
+}
+
+// isDisposable matches the domain and every parent domain against the blocklist
+func isDisposable(domain string) bool {
+	emailMu.RLock()
+	defer emailMu.RUnlock()
+	for {
+		if disposableDomains[domain] {
+			return true
+		}
+		i := strings.IndexByte(domain, '.')
+		if i < 0 {
+			return false
+		}
+		domain = domain[i+1:]
+	}
+}
+
+type emailOptions struct {
+	noDisplayName, noDisposable, mx bool
+}
+
+// parseEmailOptions reads the comma separated options of `email:`
+func parseEmailOptions(params string) (emailOptions, error) {
+	var options emailOptions
+	if params == "" {
+		return options, nil
+	}
+	for _, param := range strings.Split(params, ",") {
+		switch param {
+		case "noDisplayName":
+			options.noDisplayName = true
+		case "noDisposable":
+			options.noDisposable = true
+		case "mx":
+			options.mx = true
+		default:
+			return options, fmt.Errorf("unknown option '%s'", param)
+		}
+	}
+	return options, nil
+}
+
+// HandleEmail parses the address with net/mail semantics. Options are given as
+// `email:noDisplayName,noDisposable,mx`, where the lookups made for `mx` are
+// canceled with ctx.
+func HandleEmail(ctx context.Context, fieldValue, fieldName, opt string) string {
+	options, err := parseEmailOptions(strings.TrimPrefix(strings.TrimPrefix(opt, "email"), ":"))
+	if err != nil {
+		return fmt.Sprintf("Invalid email enforcement%s: %s", ForField(fieldName), err)
+	}
+	noDisplayName, noDisposable, checkMX := options.noDisplayName, options.noDisposable, options.mx
+
+	addr, err := mail.ParseAddress(fieldValue)
+	if err != nil {
+		return fmt.Sprintf("%s must be a valid email address", Subject(fieldName))
+	}
+	if noDisplayName && addr.Address != strings.TrimSpace(fieldValue) {
+		return fmt.Sprintf("%s must be a bare email address without a display name", Subject(fieldName))
+	}
+
+	at := strings.LastIndexByte(addr.Address, '@')
+	local, domain := addr.Address[:at], addr.Address[at+1:]
+	asciiDomain, err := toASCIIDomain(domain)
+	if err != nil || !isHostname(asciiDomain) {
+		return fmt.Sprintf("%s must be a valid email address", Subject(fieldName))
+	}
+	if len(local) > maxLocalLength {
+		return fmt.Sprintf("%s must have at most %d characters before the '@'", Subject(fieldName), maxLocalLength)
+	}
+	if len(local)+1+len(asciiDomain) > maxEmailLength {
+		return fmt.Sprintf("%s must be at most %d characters long", Subject(fieldName), maxEmailLength)
+	}
+
+	if noDisposable && isDisposable(asciiDomain) {
+		return fmt.Sprintf("%s must not use a disposable email domain", Subject(fieldName))
+	}
+	if checkMX && !acceptsMail(ctx, asciiDomain) {
+		return fmt.Sprintf("%s uses a domain that does not accept email", Subject(fieldName))
+	}
+	return ""
+}
+
+// isHostname checks an ASCII domain with at least two labels and a non-numeric TLD
+func isHostname(domain string) bool {
+	if len(domain) > maxDomainLength {
+		return false
+	}
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 || isDigits(labels[len(labels)-1]) {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > maxLabelLength || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if c != '-' && !isAlphanumeric(string(c)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// acceptsMail follows RFC 5321: use the MX records if there are any, otherwise
+// fall back to the domain's own address records. A null MX rejects all mail.
+func acceptsMail(ctx context.Context, domain string) bool {
+	emailMu.RLock()
+	resolver := emailResolver
+	emailMu.RUnlock()
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, emailLookupTimeout)
+		defer cancel()
+	}
+
+	mxs, err := resolver.LookupMX(ctx, domain)
+	if err == nil && len(mxs) > 0 {
+		for _, mx := range mxs {
+			if mx.Host != "." && mx.Host != "" {
+				return true
+			}
+		}
+		return false
+	}
+	hosts, err := resolver.LookupHost(ctx, domain)
+	return err == nil && len(hosts) > 0
+}
@@ -0,0 +1,133 @@
+package enforcements
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeResolver answers `email:mx` lookups from maps instead of DNS
+type fakeResolver struct {
+	mx    map[string][]*net.MX
+	hosts map[string][]string
+}
+
+func (r fakeResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if mxs, ok := r.mx[name]; ok {
+		return mxs, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+func (r fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if hosts, ok := r.hosts[host]; ok {
+		return hosts, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+func TestHandleEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		opt     string
+		wantErr bool
+	}{
+		{"plain", "user@example.com", "email", false},
+		{"display name", "User <user@example.com>", "email", false},
+		{"display name rejected", "User <user@example.com>", "email:noDisplayName", true},
+		{"missing at", "user.example.com", "email", true},
+		{"single label domain", "user@localhost", "email", true},
+		{"numeric tld", "user@example.123", "email", true},
+		{"label starting with hyphen", "user@-example.com", "email", true},
+		{"idn domain", "user@münchen.de", "email", false},
+		{"local part too long", strings.Repeat("a", 65) + "@example.com", "email", true},
+		{"unknown option", "user@example.com", "email:strict", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HandleEmail(context.Background(), tt.value, "Email", tt.opt)
+			if (got != "") != tt.wantErr {
+				t.Errorf("HandleEmail(%q, %q) = %q, want error %v", tt.value, tt.opt, got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandleEmailDisposable(t *testing.T) {
+	SetDisposableDomains([]string{"mailinator.com"})
+	t.Cleanup(func() { SetDisposableDomains(nil) })
+
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"user@example.com", false},
+		{"user@mailinator.com", true},
+		{"user@eu.mailinator.com", true},
+		{"user@MAILINATOR.com", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got := HandleEmail(context.Background(), tt.value, "Email", "email:noDisposable")
+			if (got != "") != tt.wantErr {
+				t.Errorf("HandleEmail(%q) = %q, want error %v", tt.value, got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandleEmailMX(t *testing.T) {
+	SetEmailResolver(fakeResolver{
+		mx: map[string][]*net.MX{
+			"example.com":       {{Host: "mx.example.com.", Pref: 10}},
+			"nullmx.example":    {{Host: ".", Pref: 0}},
+			"xn--mnchen-3ya.de": {{Host: "mx.xn--mnchen-3ya.de.", Pref: 10}},
+		},
+		hosts: map[string][]string{
+			"a-only.example": {"192.0.2.1"},
+		},
+	})
+	t.Cleanup(func() { SetEmailResolver(net.DefaultResolver) })
+
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"user@example.com", false},
+		{"user@münchen.de", false},
+		{"user@a-only.example", false},
+		{"user@nullmx.example", true},
+		{"user@missing.example", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got := HandleEmail(context.Background(), tt.value, "Email", "email:mx")
+			if (got != "") != tt.wantErr {
+				t.Errorf("HandleEmail(%q) = %q, want error %v", tt.value, got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandleEmailMXCanceled(t *testing.T) {
+	SetEmailResolver(fakeResolver{mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}}})
+	t.Cleanup(func() { SetEmailResolver(net.DefaultResolver) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got := HandleEmail(ctx, "user@example.com", "Email", "email:mx"); got == "" {
+		t.Error("HandleEmail looked up MX records with a canceled context")
+	}
+	if got := HandleEmail(ctx, "user@example.com", "Email", "email"); got != "" {
+		t.Errorf("HandleEmail without mx = %q, want no lookup", got)
+	}
+}
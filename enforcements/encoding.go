@@ -0,0 +1,145 @@
+package enforcements
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseByteSize reads sizes like "512", "64B", "16KB", "1MB" or "2GB" using
+// 1024 based units
+func parseByteSize(s string) (int, error) {
+	units := []struct {
+		suffix string
+		size   int
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}}
+
+	multiplier := 1
+	upper := strings.ToUpper(s)
+	for _, unit := range units {
+		if strings.HasSuffix(upper, unit.suffix) {
+			upper = strings.TrimSuffix(upper, unit.suffix)
+			multiplier = unit.size
+			break
+		}
+	}
+	n, err := strconv.Atoi(upper)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size '%s'", s)
+	}
+	return n * multiplier, nil
+}
+
+// parseMaxDecoded reads the `maxDecoded=<size>` parameter of an encoding
+// enforcement, returning -1 when there is no limit
+func parseMaxDecoded(param string) (int, error) {
+	if param == "" {
+		return -1, nil
+	}
+	if !strings.HasPrefix(param, "maxDecoded=") {
+		return 0, fmt.Errorf("unknown option '%s'", param)
+	}
+	return parseByteSize(strings.TrimPrefix(param, "maxDecoded="))
+}
+
+func HandleBase64(fieldValue, fieldName, opt string) string {
+	return handleBase64(fieldValue, fieldName, opt, "base64", base64.StdEncoding)
+}
+
+// HandleBase64URL accepts the URL safe alphabet both with and without padding
+func HandleBase64URL(fieldValue, fieldName, opt string) string {
+	encoding := base64.RawURLEncoding
+	if strings.HasSuffix(fieldValue, "=") {
+		encoding = base64.URLEncoding
+	}
+	return handleBase64(fieldValue, fieldName, opt, "base64url", encoding)
+}
+
+func handleBase64(fieldValue, fieldName, opt, name string, encoding *base64.Encoding) string {
+	maxDecoded, err := parseMaxDecoded(strings.TrimPrefix(strings.TrimPrefix(opt, name), ":"))
+	if err != nil {
+		return fmt.Sprintf("Invalid %s enforcement%s: %s", name, ForField(fieldName), err)
+	}
+
+	// The decoder silently skips line breaks, which a single field value should never contain
+	if strings.ContainsAny(fieldValue, "\r\n") {
+		return fmt.Sprintf("%s must be valid %s", Subject(fieldName), name)
+	}
+	// Check the size before decoding so oversized payloads are never allocated
+	if maxDecoded >= 0 && encoding.DecodedLen(len(fieldValue)) > maxDecoded+2 {
+		return fmt.Sprintf("%s must decode to at most %d bytes", Subject(fieldName), maxDecoded)
+	}
+	decoded, err := encoding.Strict().DecodeString(fieldValue)
+	if err != nil {
+		return fmt.Sprintf("%s must be valid %s", Subject(fieldName), name)
+	}
+	if maxDecoded >= 0 && len(decoded) > maxDecoded {
+		return fmt.Sprintf("%s must decode to at most %d bytes", Subject(fieldName), maxDecoded)
+	}
+	return ""
+}
+
+func HandleHex(fieldValue, fieldName, opt string) string {
+	maxDecoded, err := parseMaxDecoded(strings.TrimPrefix(strings.TrimPrefix(opt, "hex"), ":"))
+	if err != nil {
+		return fmt.Sprintf("Invalid hex enforcement%s: %s", ForField(fieldName), err)
+	}
+
+	// Like base64, check the size before decoding
+	if maxDecoded >= 0 && hex.DecodedLen(len(fieldValue)) > maxDecoded {
+		return fmt.Sprintf("%s must decode to at most %d bytes", Subject(fieldName), maxDecoded)
+	}
+	if _, err := hex.DecodeString(fieldValue); err != nil {
+		return fmt.Sprintf("%s must be valid hex", Subject(fieldName))
+	}
+	return ""
+}
+
+// jsonTopLevels maps the options of `json:` to the character the top level
+// value has to start with
+var jsonTopLevels = map[string]string{"": "", "object": "{", "array": "["}
+
+// HandleJSON checks that the value is syntactically valid JSON. `json:object`
+// and `json:array` additionally require that top level type.
+func HandleJSON(fieldValue, fieldName, opt string) string {
+	topLevel := strings.TrimPrefix(strings.TrimPrefix(opt, "json"), ":")
+	start, ok := jsonTopLevels[topLevel]
+	if !ok {
+		return fmt.Sprintf("Invalid json enforcement%s", ForField(fieldName))
+	}
+
+	if !json.Valid([]byte(fieldValue)) {
+		return fmt.Sprintf("%s must be valid JSON", Subject(fieldName))
+	}
+	if !strings.HasPrefix(strings.TrimLeft(fieldValue, " \t\r\n"), start) {
+		return fmt.Sprintf("%s must be a JSON %s", Subject(fieldName), topLevel)
+	}
+	return ""
+}
+
+// HandleJWT checks the structure of a compact JWS: three base64url segments
+// whose header and claims decode to JSON objects. Signatures are not verified.
+func HandleJWT(fieldValue, fieldName string) string {
+	segments := strings.Split(fieldValue, ".")
+	if len(segments) != 3 {
+		return fmt.Sprintf("%s must be a JWT with three segments", Subject(fieldName))
+	}
+
+	for i, part := range []string{"header", "claims"} {
+		decoded, err := base64.RawURLEncoding.DecodeString(segments[i])
+		if err != nil {
+			return fmt.Sprintf("%s must have a base64url encoded JWT %s", Subject(fieldName), part)
+		}
+		var obj map[string]interface{}
+		if err := json.Unmarshal(decoded, &obj); err != nil || obj == nil {
+			return fmt.Sprintf("%s must have a JSON object as the JWT %s", Subject(fieldName), part)
+		}
+	}
+	if _, err := base64.RawURLEncoding.DecodeString(segments[2]); err != nil {
+		return fmt.Sprintf("%s must have a base64url encoded JWT signature", Subject(fieldName))
+	}
+	return ""
+}
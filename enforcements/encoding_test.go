@@ -0,0 +1,97 @@
+package enforcements
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		size    string
+		want    int
+		wantErr bool
+	}{
+		{"512", 512, false},
+		{"64B", 64, false},
+		{"16KB", 16 << 10, false},
+		{"1mb", 1 << 20, false},
+		{"2GB", 2 << 30, false},
+		{"", 0, true},
+		{"KB", 0, true},
+		{"-1KB", 0, true},
+		{"1.5MB", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseByteSize(tt.size)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseByteSize(%q) = %d, %v, want %d", tt.size, got, err, tt.want)
+		}
+	}
+}
+
+func TestEncodingEnforcements(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(fieldValue, fieldName, opt string) string
+		value   string
+		opt     string
+		wantErr string
+	}{
+		{"base64", HandleBase64, "aGVsbG8=", "base64", ""},
+		{"base64", HandleBase64, "aGVsbG8", "base64", "must be valid base64"},
+		{"base64", HandleBase64, "aGVs\nbG8=", "base64", "must be valid base64"},
+		{"base64", HandleBase64, "aGVsbG8=", "base64:maxDecoded=5B", ""},
+		{"base64", HandleBase64, "aGVsbG8=", "base64:maxDecoded=4", "must decode to at most 4 bytes"},
+		{"base64", HandleBase64, "aGVsbG8=", "base64:maxSize=4", "Invalid base64 enforcement"},
+		{"base64url", HandleBase64URL, "_-8", "base64url", ""},
+		{"base64url", HandleBase64URL, "_-8=", "base64url", ""},
+		{"base64url", HandleBase64URL, "+/8=", "base64url", "must be valid base64url"},
+		{"hex", HandleHex, "deadBEEF", "hex", ""},
+		{"hex", HandleHex, "abc", "hex", "must be valid hex"},
+		{"hex", HandleHex, "xyz0", "hex", "must be valid hex"},
+		{"hex", HandleHex, "deadbeef", "hex:maxDecoded=4", ""},
+		{"hex", HandleHex, "deadbeef00", "hex:maxDecoded=4", "must decode to at most 4 bytes"},
+		// Oversized values are rejected before they are decoded
+		{"hex", HandleHex, strings.Repeat("z", 10), "hex:maxDecoded=4", "must decode to at most 4 bytes"},
+		{"hex", HandleHex, "dead", "hex:maxDecoded=big", "Invalid hex enforcement"},
+		{"json", HandleJSON, `{"a": [1, 2]}`, "json", ""},
+		{"json", HandleJSON, `"text"`, "json", ""},
+		{"json", HandleJSON, `{"a": }`, "json", "must be valid JSON"},
+		{"json", HandleJSON, ` {"a": 1}`, "json:object", ""},
+		{"json", HandleJSON, `[1]`, "json:object", "must be a JSON object"},
+		{"json", HandleJSON, `[1]`, "json:array", ""},
+		{"json", HandleJSON, `{}`, "json:array", "must be a JSON array"},
+		// Mistakes in the option are reported whatever the value
+		{"json", HandleJSON, `{}`, "json:objekt", "Invalid json enforcement"},
+		{"json", HandleJSON, `{`, "json:objekt", "Invalid json enforcement"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.opt+" "+tt.value, func(t *testing.T) {
+			got := tt.handler(tt.value, "Payload", tt.opt)
+			if (got == "") != (tt.wantErr == "") || !strings.Contains(got, tt.wantErr) {
+				t.Errorf("%s(%q, %q) = %q, want %q", tt.name, tt.value, tt.opt, got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandleJWT(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr string
+	}{
+		{"eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln", ""},
+		{"eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.", ""},
+		{"eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0", "three segments"},
+		{"e30=.e30.c2ln", "base64url encoded JWT header"},
+		{"WzFd.e30.c2ln", "JSON object as the JWT header"},
+		{"e30.bnVsbA.c2ln", "JSON object as the JWT claims"},
+		{"e30.e30.c2ln+", "base64url encoded JWT signature"},
+	}
+	for _, tt := range tests {
+		got := HandleJWT(tt.value, "Token")
+		if (got == "") != (tt.wantErr == "") || !strings.Contains(got, tt.wantErr) {
+			t.Errorf("HandleJWT(%q) = %q, want %q", tt.value, got, tt.wantErr)
+		}
+	}
+}
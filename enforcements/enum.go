@@ -14,7 +14,7 @@ func HandleEnumStr(fieldValue, fieldName, opt string) string {
 			return "" // Value is in the enum, no error
 		}
 	}
-	return fmt.Sprintf("Field '%s' does not match any valid enum value", fieldName)
+	return fmt.Sprintf("%s does not match any valid enum value", Subject(fieldName))
 }
 
 func HandleEnumIntOrFloat(value interface{}, fieldName string, enumOptions string) string {
@@ -24,7 +24,7 @@ func HandleEnumIntOrFloat(value interface{}, fieldName string, enumOptions strin
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			enum, err := strconv.ParseInt(enumStr, 10, 64)
 			if err != nil {
-				return fmt.Sprintf("Invalid enum value '%s' for field '%s'", enumStr, fieldName)
+				return fmt.Sprintf("Invalid enum value '%s'%s", enumStr, ForField(fieldName))
 			}
 			if reflect.ValueOf(value).Int() == enum {
 				return "" // Value is in the enum, no error
@@ -32,7 +32,7 @@ func HandleEnumIntOrFloat(value interface{}, fieldName string, enumOptions strin
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 			enum, err := strconv.ParseUint(enumStr, 10, 64)
 			if err != nil {
-				return fmt.Sprintf("Invalid enum value '%s' for field '%s'", enumStr, fieldName)
+				return fmt.Sprintf("Invalid enum value '%s'%s", enumStr, ForField(fieldName))
 			}
 			if reflect.ValueOf(value).Uint() == enum {
 				return "" // Value is in the enum, no error
@@ -40,18 +40,18 @@ func HandleEnumIntOrFloat(value interface{}, fieldName string, enumOptions strin
 		case reflect.Float32, reflect.Float64:
 			enum, err := strconv.ParseFloat(enumStr, 64)
 			if err != nil {
-				return fmt.Sprintf("Invalid enum value '%s' for field '%s'", enumStr, fieldName)
+				return fmt.Sprintf("Invalid enum value '%s'%s", enumStr, ForField(fieldName))
 			}
 			if reflect.ValueOf(value).Float() == enum {
 				return "" // Value is in the enum, no error
 			}
 		default:
-			return fmt.Sprintf("Unsupported type for field '%s'", fieldName)
+			return fmt.Sprintf("Unsupported type%s", ForField(fieldName))
 		}
 	}
 
 	return fmt.Sprintf(
-		"Field '%s' does not match any enum values: %s",
-		fieldName, strings.Join(enumValues, ", "),
+		"%s does not match any enum values: %s",
+		Subject(fieldName), strings.Join(enumValues, ", "),
 	)
 }
@@ -0,0 +1,36 @@
+package enforcements
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// HandleEqField checks that a field equals another field of the same struct,
+// as in `eqfield:Password` on a password confirmation
+func HandleEqField(fieldValue reflect.Value, fieldName, opt string, sibling SiblingLookup) string {
+	otherName := strings.TrimPrefix(opt, "eqfield:")
+	if otherName == "" || otherName == opt {
+		return fmt.Sprintf("Invalid eqfield enforcement%s: a field name is required", ForField(fieldName))
+	}
+	if sibling == nil {
+		return fmt.Sprintf("Invalid eqfield enforcement%s: field '%s' is only available on structs", ForField(fieldName), otherName)
+	}
+	other, ok := sibling(otherName)
+	if !ok || !other.CanInterface() {
+		return fmt.Sprintf("Invalid eqfield enforcement%s: field '%s' not found", ForField(fieldName), otherName)
+	}
+	if other.Kind() == reflect.Ptr {
+		if other.IsNil() {
+			return fmt.Sprintf("%s must be equal to '%s'", Subject(fieldName), otherName)
+		}
+		other = other.Elem()
+	}
+	if other.Type() != fieldValue.Type() {
+		return fmt.Sprintf("Invalid eqfield enforcement%s: field '%s' has a different type", ForField(fieldName), otherName)
+	}
+	if !reflect.DeepEqual(fieldValue.Interface(), other.Interface()) {
+		return fmt.Sprintf("%s must be equal to '%s'", Subject(fieldName), otherName)
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package enforcements
+
+import "fmt"
+
+// FieldError is a single failed enforcement. Params carries values computed
+// while checking, such as the age found by `minAge`, for callers that need
+// more than the message.
+type FieldError struct {
+	// Field is the path of resolved field names, like `Billing.Items[2].Qty`
+	Field string
+	// Path is the dotted path of JSON keys, like `billing.items.2.qty`
+	Path string
+	// Pointer is the RFC 6901 JSON Pointer to the value in the request body,
+	// like `/billing/items/2/qty`. Path and Pointer are empty for fields that
+	// have no JSON key.
+	Pointer string
+	Rule    string
+	Message string
+	Params  map[string]interface{}
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// SchemaError is a mistake in the enforce tags of a struct or in how they are
+// used, such as a bad rule parameter or a default of the wrong type. It is
+// meant for developers and should never be shown to clients.
+type SchemaError struct {
+	Struct  string
+	Field   string
+	Rule    string
+	Message string
+}
+
+func (e *SchemaError) Error() string {
+	switch {
+	case e.Struct != "" && e.Field != "":
+		return fmt.Sprintf("enforcer: %s.%s: %s", e.Struct, e.Field, e.Message)
+	case e.Field != "":
+		return fmt.Sprintf("enforcer: %s: %s", e.Field, e.Message)
+	}
+	return "enforcer: " + e.Message
+}
+
+// ConfigError is a mistake in a tag that only shows up while checking a value,
+// such as an eqfield target that does not exist. Rules return it to have the
+// mistake reported as a *SchemaError instead of a failure of the value.
+type ConfigError struct {
+	Message string
+}
+
+func (e *ConfigError) Error() string {
+	return e.Message
+}
@@ -11,7 +11,7 @@ func HandleExcludeStr(fieldValue, fieldName, opt string) string {
 	excludeValues := strings.Split(strings.TrimPrefix(opt, "exclude:"), ",")
 	for _, exclude := range excludeValues {
 		if fieldValue == exclude {
-			return fmt.Sprintf("Field '%s' contains excluded value: %s", fieldName, exclude)
+			return fmt.Sprintf("%s contains excluded value: %s", Subject(fieldName), exclude)
 		}
 	}
 	return ""
@@ -24,29 +24,29 @@ func HandleExcludeIntOrFloat(value interface{}, fieldName string, excludeOptions
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			exclude, err := strconv.ParseInt(enumStr, 10, 64)
 			if err != nil {
-				return fmt.Sprintf("Invalid exclude value '%s' for field '%s'", enumStr, fieldName)
+				return fmt.Sprintf("Invalid exclude value '%s'%s", enumStr, ForField(fieldName))
 			}
 			if reflect.ValueOf(value).Int() == exclude {
-				return fmt.Sprintf("Field '%s' contains excluded value: %d", fieldName, exclude)
+				return fmt.Sprintf("%s contains excluded value: %d", Subject(fieldName), exclude)
 			}
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 			exclude, err := strconv.ParseUint(enumStr, 10, 64)
 			if err != nil {
-				return fmt.Sprintf("Invalid exclude value '%s' for field '%s'", enumStr, fieldName)
+				return fmt.Sprintf("Invalid exclude value '%s'%s", enumStr, ForField(fieldName))
 			}
 			if reflect.ValueOf(value).Uint() == exclude {
-				return fmt.Sprintf("Field '%s' contains excluded value: %d", fieldName, exclude)
+				return fmt.Sprintf("%s contains excluded value: %d", Subject(fieldName), exclude)
 			}
 		case reflect.Float32, reflect.Float64:
 			exclude, err := strconv.ParseFloat(enumStr, 64)
 			if err != nil {
-				return fmt.Sprintf("Invalid exclude value '%s' for field '%s'", enumStr, fieldName)
+				return fmt.Sprintf("Invalid exclude value '%s'%s", enumStr, ForField(fieldName))
 			}
 			if reflect.ValueOf(value).Float() == exclude {
-				return fmt.Sprintf("Field '%s' contains excluded value: %f", fieldName, exclude)
+				return fmt.Sprintf("%s contains excluded value: %f", Subject(fieldName), exclude)
 			}
 		default:
-			return fmt.Sprintf("Unsupported type for field '%s'", fieldName)
+			return fmt.Sprintf("Unsupported type%s", ForField(fieldName))
 		}
 	}
 
@@ -0,0 +1,181 @@
+package enforcements
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// BoundingBox is a rectangular region in degrees. A box whose MinLng is greater
+// than its MaxLng is treated as crossing the antimeridian.
+type BoundingBox struct {
+	MinLat float64
+	MinLng float64
+	MaxLat float64
+	MaxLng float64
+}
+
+// Contains reports whether the point lies inside the box, edges included
+func (b BoundingBox) Contains(lat, lng float64) bool {
+	if lat < b.MinLat || lat > b.MaxLat {
+		return false
+	}
+	if b.MinLng <= b.MaxLng {
+		return lng >= b.MinLng && lng <= b.MaxLng
+	}
+	return lng >= b.MinLng || lng <= b.MaxLng
+}
+
+var (
+	regionsMu sync.RWMutex
+	regions   = map[string]BoundingBox{}
+)
+
+// RegisterRegion makes a named bounding box available to `within:<name>`.
+// Registering the same name twice replaces the previous box.
+func RegisterRegion(name string, box BoundingBox) {
+	regionsMu.Lock()
+	defer regionsMu.Unlock()
+	regions[name] = box
+	registrations.Add(1)
+}
+
+func lookupRegion(name string) (BoundingBox, bool) {
+	regionsMu.RLock()
+	defer regionsMu.RUnlock()
+	box, ok := regions[name]
+	return box, ok
+}
+
+func HandleLatitude(value interface{}, fieldName string) string {
+	lat, ok := toFloat(value)
+	if !ok || !(lat >= -90 && lat <= 90) {
+		return fmt.Sprintf("%s must be a valid latitude between -90 and 90", Subject(fieldName))
+	}
+	return ""
+}
+
+func HandleLongitude(value interface{}, fieldName string) string {
+	lng, ok := toFloat(value)
+	if !ok || !(lng >= -180 && lng <= 180) {
+		return fmt.Sprintf("%s must be a valid longitude between -180 and 180", Subject(fieldName))
+	}
+	return ""
+}
+
+func HandleLatLng(fieldValue, fieldName string) string {
+	if _, _, ok := parseLatLng(fieldValue); !ok {
+		return fmt.Sprintf("%s must be a coordinate pair in the form 'lat,lng'", Subject(fieldName))
+	}
+	return ""
+}
+
+func HandleGeohash(fieldValue, fieldName, opt string) string {
+	maxPrecision := 12
+	if limit := strings.TrimPrefix(opt, "geohash"); limit != "" {
+		p, err := strconv.Atoi(strings.TrimPrefix(limit, ":"))
+		if err != nil || p < 1 || p > 12 {
+			return fmt.Sprintf("Invalid geohash precision%s", ForField(fieldName))
+		}
+		maxPrecision = p
+	}
+
+	if fieldValue == "" || len(fieldValue) > maxPrecision {
+		return fmt.Sprintf("%s must be a geohash of at most %d characters", Subject(fieldName), maxPrecision)
+	}
+	for _, c := range strings.ToLower(fieldValue) {
+		if !strings.ContainsRune(geohashAlphabet, c) {
+			return fmt.Sprintf("%s must be a valid geohash", Subject(fieldName))
+		}
+	}
+	return ""
+}
+
+// HandleWithin checks that a point lies inside a bounding box. The point can be a
+// "lat,lng" string or a two element float slice or array in [lat, lng] order.
+// The box is either "minLat,minLng,maxLat,maxLng" or the name of a registered region.
+func HandleWithin(value interface{}, fieldName, opt string) string {
+	box, ok := parseBoundingBox(strings.TrimPrefix(opt, "within:"))
+	if !ok {
+		return fmt.Sprintf("Invalid bounding box or unknown region%s", ForField(fieldName))
+	}
+
+	lat, lng, ok := toPoint(value)
+	if !ok {
+		return fmt.Sprintf("%s must be a coordinate pair in the form 'lat,lng'", Subject(fieldName))
+	}
+	if !box.Contains(lat, lng) {
+		return fmt.Sprintf("%s must be within %s", Subject(fieldName), strings.TrimPrefix(opt, "within:"))
+	}
+	return ""
+}
+
+func parseBoundingBox(s string) (BoundingBox, bool) {
+	if box, ok := lookupRegion(s); ok {
+		return box, true
+	}
+
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return BoundingBox{}, false
+	}
+	var bounds [4]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return BoundingBox{}, false
+		}
+		bounds[i] = f
+	}
+	box := BoundingBox{MinLat: bounds[0], MinLng: bounds[1], MaxLat: bounds[2], MaxLng: bounds[3]}
+	if !validLatLng(box.MinLat, box.MinLng) || !validLatLng(box.MaxLat, box.MaxLng) || box.MinLat > box.MaxLat {
+		return BoundingBox{}, false
+	}
+	return box, true
+}
+
+func parseLatLng(s string) (float64, float64, bool) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return lat, lng, validLatLng(lat, lng)
+}
+
+func validLatLng(lat, lng float64) bool {
+	return lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}
+
+func toPoint(value interface{}) (float64, float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String:
+		return parseLatLng(v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Len() != 2 {
+			return 0, 0, false
+		}
+		lat, ok := toFloat(v.Index(0).Interface())
+		if !ok {
+			return 0, 0, false
+		}
+		lng, ok := toFloat(v.Index(1).Interface())
+		if !ok {
+			return 0, 0, false
+		}
+		return lat, lng, validLatLng(lat, lng)
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,110 @@
+package enforcements
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHandleCoordinates(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func() string
+		valid bool
+	}{
+		{"latitude", func() string { return HandleLatitude(27.7, "Lat") }, true},
+		{"latitude edge", func() string { return HandleLatitude(-90, "Lat") }, true},
+		{"latitude string", func() string { return HandleLatitude(" 45.5 ", "Lat") }, true},
+		{"latitude out of range", func() string { return HandleLatitude(90.1, "Lat") }, false},
+		{"latitude NaN", func() string { return HandleLatitude(math.NaN(), "Lat") }, false},
+		{"latitude text", func() string { return HandleLatitude("north", "Lat") }, false},
+		{"longitude", func() string { return HandleLongitude(uint8(180), "Lng") }, true},
+		{"longitude out of range", func() string { return HandleLongitude(-180.5, "Lng") }, false},
+		{"latlng", func() string { return HandleLatLng("27.7, 85.3", "Point") }, true},
+		{"latlng out of range", func() string { return HandleLatLng("91,0", "Point") }, false},
+		{"latlng single value", func() string { return HandleLatLng("27.7", "Point") }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.check(); (got == "") != tt.valid {
+				t.Errorf("got %q, want valid %v", got, tt.valid)
+			}
+		})
+	}
+}
+
+func TestHandleGeohash(t *testing.T) {
+	tests := []struct {
+		value, opt string
+		valid      bool
+	}{
+		{"tuvz4p141zc1", "geohash", true},
+		{"TUVZ", "geohash", true},
+		{"tuvz4p141zc1f", "geohash", false},
+		{"tuvz4", "geohash:5", true},
+		{"tuvz4p", "geohash:5", false},
+		{"tuva", "geohash", false},
+		{"", "geohash", false},
+	}
+	for _, tt := range tests {
+		if got := HandleGeohash(tt.value, "Hash", tt.opt); (got == "") != tt.valid {
+			t.Errorf("HandleGeohash(%q, %q) = %q, want valid %v", tt.value, tt.opt, got, tt.valid)
+		}
+	}
+}
+
+func TestHandleWithin(t *testing.T) {
+	RegisterRegion("test-ktm", BoundingBox{MinLat: 27.6, MinLng: 85.2, MaxLat: 27.8, MaxLng: 85.5})
+	tests := []struct {
+		name  string
+		value interface{}
+		opt   string
+		valid bool
+	}{
+		{"region", "27.7,85.3", "within:test-ktm", true},
+		{"outside region", "27.7,86", "within:test-ktm", false},
+		{"box", []float64{1, 1}, "within:0,0,2,2", true},
+		{"box edge", [2]float64{2, 0}, "within:0,0,2,2", true},
+		{"outside box", []float64{3, 1}, "within:0,0,2,2", false},
+		{"antimeridian", "0,179.5", "within:-1,179,1,-179", true},
+		{"across antimeridian", "0,-179.5", "within:-1,179,1,-179", true},
+		{"outside antimeridian box", "0,0", "within:-1,179,1,-179", false},
+		{"three element point", []float64{1, 1, 1}, "within:0,0,2,2", false},
+		{"bad point", "here", "within:0,0,2,2", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HandleWithin(tt.value, "Point", tt.opt); (got == "") != tt.valid {
+				t.Errorf("HandleWithin(%v, %q) = %q, want valid %v", tt.value, tt.opt, got, tt.valid)
+			}
+		})
+	}
+}
+
+func TestParseBoundingBox(t *testing.T) {
+	tests := []struct {
+		box   string
+		valid bool
+	}{
+		{"0,0,2,2", true},
+		{" -1 , 179 , 1 , -179 ", true},
+		{"0,0,2", false},
+		{"a,0,2,2", false},
+		{"2,0,0,2", false},
+		{"0,0,91,2", false},
+		{"0,-181,2,2", false},
+		{"unknown-region", false},
+	}
+	for _, tt := range tests {
+		if _, ok := parseBoundingBox(tt.box); ok != tt.valid {
+			t.Errorf("parseBoundingBox(%q) = %v, want %v", tt.box, ok, tt.valid)
+		}
+		if err := ParseParam("within", tt.box); (err == nil) != tt.valid {
+			t.Errorf("ParseParam(within, %q) = %v, want valid %v", tt.box, err, tt.valid)
+		}
+	}
+	for _, precision := range []string{"0", "13", "x", "-1"} {
+		if err := ParseParam("geohash", precision); err == nil {
+			t.Errorf("ParseParam(geohash, %q) = nil, want an error", precision)
+		}
+	}
+}
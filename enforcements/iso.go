@@ -0,0 +1,239 @@
+package enforcements
+
+import (
+	_ "embed"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	// Embedded zone data keeps `timezone` working on hosts without a zoneinfo database
+	_ "time/tzdata"
+)
+
+//go:embed data/iso3166.tsv
+var iso3166Table string
+
+//go:embed data/iso639.tsv
+var iso639Table string
+
+var (
+	isoCodesOnce   sync.Once
+	countryAlpha2  map[string]bool
+	countryAlpha3  map[string]bool
+	countryNumeric map[string]bool
+	languageAlpha2 map[string]bool
+)
+
+func loadISOCodes() {
+	isoCodesOnce.Do(func() {
+		countryAlpha2 = map[string]bool{}
+		countryAlpha3 = map[string]bool{}
+		countryNumeric = map[string]bool{}
+		for _, cols := range tableRows(iso3166Table) {
+			countryAlpha2[cols[0]] = true
+			countryAlpha3[cols[1]] = true
+			countryNumeric[cols[2]] = true
+		}
+
+		languageAlpha2 = map[string]bool{}
+		for _, cols := range tableRows(iso639Table) {
+			languageAlpha2[cols[0]] = true
+		}
+	})
+}
+
+// tableRows splits an embedded tab separated table, skipping comments and blank lines
+func tableRows(table string) [][]string {
+	var rows [][]string
+	for _, line := range strings.Split(table, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rows = append(rows, strings.Split(line, "\t"))
+	}
+	return rows
+}
+
+// HandleCountry checks an ISO 3166-1 country code. `country` accepts any of the
+// three forms while `country:alpha2`, `country:alpha3` and `country:numeric`
+// restrict it to one. Numeric codes may also be stored in integer fields.
+func HandleCountry(value interface{}, fieldName, opt string) string {
+	loadISOCodes()
+
+	v := reflect.ValueOf(value)
+	code := ""
+	switch {
+	case IsString(v.Kind()):
+		code = strings.ToUpper(v.String())
+	case IsIntType(v.Kind()):
+		code = fmt.Sprintf("%03d", v.Int())
+	case IsUintType(v.Kind()):
+		code = fmt.Sprintf("%03d", v.Uint())
+	default:
+		return fmt.Sprintf("Unsupported type%s", ForField(fieldName))
+	}
+
+	valid := false
+	switch strings.TrimPrefix(opt, "country") {
+	case "":
+		valid = countryAlpha2[code] || countryAlpha3[code] || countryNumeric[code]
+	case ":alpha2":
+		valid = countryAlpha2[code]
+	case ":alpha3":
+		valid = countryAlpha3[code]
+	case ":numeric":
+		valid = countryNumeric[code]
+	default:
+		return fmt.Sprintf("Invalid country code format%s", ForField(fieldName))
+	}
+
+	if !valid {
+		return fmt.Sprintf("%s must be a valid ISO 3166-1 country code", Subject(fieldName))
+	}
+	return ""
+}
+
+func HandleLanguage(fieldValue, fieldName string) string {
+	loadISOCodes()
+
+	if !languageAlpha2[strings.ToLower(fieldValue)] {
+		return fmt.Sprintf("%s must be a valid ISO 639-1 language code", Subject(fieldName))
+	}
+	return ""
+}
+
+func HandleLocale(fieldValue, fieldName string) string {
+	if !isLocaleTag(fieldValue) {
+		return fmt.Sprintf("%s must be a valid BCP 47 locale tag", Subject(fieldName))
+	}
+	return ""
+}
+
+func HandleTimezone(fieldValue, fieldName string) string {
+	// LoadLocation maps "" and "Local" to the host zone, which is never what a client means
+	if fieldValue == "" || fieldValue == "Local" {
+		return fmt.Sprintf("%s must be a valid IANA time zone", Subject(fieldName))
+	}
+	if _, err := time.LoadLocation(fieldValue); err != nil {
+		return fmt.Sprintf("%s must be a valid IANA time zone", Subject(fieldName))
+	}
+	return ""
+}
+
+// isLocaleTag checks the RFC 5646 language tag syntax. Two letter languages and
+// regions are also checked against the ISO tables; longer subtags are only
+// checked for shape since the full IANA registry is not embedded.
+func isLocaleTag(tag string) bool {
+	loadISOCodes()
+
+	subtags := strings.Split(strings.ToLower(tag), "-")
+	if subtags[0] == "x" {
+		return isPrivateUse(subtags[1:])
+	}
+
+	// language, with up to three extended language subtags
+	language := subtags[0]
+	if !isAlpha(language) || len(language) < 2 || len(language) > 8 {
+		return false
+	}
+	if len(language) == 2 && !languageAlpha2[language] {
+		return false
+	}
+	i := 1
+	if len(language) <= 3 {
+		for extlangs := 0; i < len(subtags) && extlangs < 3 && len(subtags[i]) == 3 && isAlpha(subtags[i]); i++ {
+			extlangs++
+		}
+	}
+
+	// script
+	if i < len(subtags) && len(subtags[i]) == 4 && isAlpha(subtags[i]) {
+		i++
+	}
+
+	// region
+	if i < len(subtags) {
+		region := subtags[i]
+		if len(region) == 2 && isAlpha(region) {
+			if !countryAlpha2[strings.ToUpper(region)] {
+				return false
+			}
+			i++
+		} else if len(region) == 3 && isDigits(region) {
+			i++
+		}
+	}
+
+	// variants
+	for i < len(subtags) && isVariant(subtags[i]) {
+		i++
+	}
+
+	// extensions, each a singleton followed by at least one 2-8 character subtag
+	for i < len(subtags) && len(subtags[i]) == 1 && subtags[i] != "x" {
+		if !isAlphanumeric(subtags[i]) {
+			return false
+		}
+		i++
+		start := i
+		for i < len(subtags) && len(subtags[i]) >= 2 && len(subtags[i]) <= 8 && isAlphanumeric(subtags[i]) {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+
+	if i < len(subtags) && subtags[i] == "x" {
+		return isPrivateUse(subtags[i+1:])
+	}
+	return i == len(subtags)
+}
+
+func isPrivateUse(subtags []string) bool {
+	if len(subtags) == 0 {
+		return false
+	}
+	for _, s := range subtags {
+		if len(s) < 1 || len(s) > 8 || !isAlphanumeric(s) {
+			return false
+		}
+	}
+	return true
+}
+
+func isVariant(s string) bool {
+	if !isAlphanumeric(s) {
+		return false
+	}
+	return (len(s) >= 5 && len(s) <= 8) || (len(s) == 4 && s[0] >= '0' && s[0] <= '9')
+}
+
+func isAlpha(s string) bool {
+	for _, c := range s {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isAlphanumeric(s string) bool {
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return s != ""
+}
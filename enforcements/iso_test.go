@@ -0,0 +1,102 @@
+package enforcements
+
+import "testing"
+
+func TestIsLocaleTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want bool
+	}{
+		{"en", true},
+		{"en-US", true},
+		{"zh-Hant-TW", true},
+		{"sr-Latn-RS", true},
+		{"es-419", true},
+		{"de-CH-1901", true},
+		{"sl-rozaj-biske", true},
+		{"en-US-u-ca-gregory", true},
+		{"zh-yue-HK", true},
+		{"en-x-private", true},
+		{"x-whatever", true},
+		{"", false},
+		{"e", false},
+		{"zz", false},
+		{"en-ZZ", false},
+		{"en_US", false},
+		{"en-", false},
+		{"en-u", false},
+		{"en-x", false},
+		{"toolonglanguage", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := isLocaleTag(tt.tag); got != tt.want {
+				t.Errorf("isLocaleTag(%q) = %v, want %v", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleCountry(t *testing.T) {
+	tests := []struct {
+		value   interface{}
+		opt     string
+		wantErr bool
+	}{
+		{"NP", "country", false},
+		{"np", "country", false},
+		{"NPL", "country", false},
+		{"524", "country", false},
+		{524, "country", false},
+		{uint16(840), "country:numeric", false},
+		{"US", "country:alpha2", false},
+		{"USA", "country:alpha2", true},
+		{"USA", "country:alpha3", false},
+		{"US", "country:numeric", true},
+		{"XX", "country", true},
+		{999, "country", true},
+	}
+	for _, tt := range tests {
+		got := HandleCountry(tt.value, "Country", tt.opt)
+		if (got != "") != tt.wantErr {
+			t.Errorf("HandleCountry(%v, %q) = %q, want error %v", tt.value, tt.opt, got, tt.wantErr)
+		}
+	}
+}
+
+func TestHandleLanguage(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"en", false},
+		{"NE", false},
+		{"eng", true},
+		{"zz", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		if got := HandleLanguage(tt.value, "Language"); (got != "") != tt.wantErr {
+			t.Errorf("HandleLanguage(%q) = %q, want error %v", tt.value, got, tt.wantErr)
+		}
+	}
+}
+
+func TestHandleTimezone(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"Asia/Kathmandu", false},
+		{"UTC", false},
+		{"America/Argentina/Buenos_Aires", false},
+		{"Local", true},
+		{"", true},
+		{"Mars/Olympus", true},
+	}
+	for _, tt := range tests {
+		if got := HandleTimezone(tt.value, "Zone"); (got != "") != tt.wantErr {
+			t.Errorf("HandleTimezone(%q) = %q, want error %v", tt.value, got, tt.wantErr)
+		}
+	}
+}
@@ -9,12 +9,12 @@ import (
 func matchPattern(pattern, fieldValue, fieldName, customErrorMessage string) string {
 	match, err := regexp.MatchString(pattern, fieldValue)
 	if err != nil {
-		return fmt.Sprintf("Invalid pattern for field '%s' %s", fieldName, err)
+		return fmt.Sprintf("Invalid pattern%s %s", ForField(fieldName), err)
 	} else if !match {
 		if customErrorMessage != "" {
 			return customErrorMessage
 		}
-		return fmt.Sprintf("Field '%s' does not match email pattern", fieldName)
+		return fmt.Sprintf("%s does not match email pattern", Subject(fieldName))
 	}
 	return ""
 }
@@ -25,21 +25,21 @@ func HandleMatch(fieldValue, fieldName, opt string) string {
 	case "match:email":
 		pattern = `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
 	case "match:phone":
-		pattern = `^[0-9\\-]{7,12}$`
+		pattern = `^[0-9\-]{7,12}$`
 	case "match:password":
 		// At least one uppercase letter, one lowercase letter,
 		// one digit, and one special character
 		if !containsUppercase(fieldValue) {
-			return fmt.Sprintf("Field '%s' must contain at least one uppercase letter", fieldName)
+			return fmt.Sprintf("%s must contain at least one uppercase letter", Subject(fieldName))
 		}
 		if !containsLowercase(fieldValue) {
-			return fmt.Sprintf("Field '%s' must contain at least one lowercase letter", fieldName)
+			return fmt.Sprintf("%s must contain at least one lowercase letter", Subject(fieldName))
 		}
 		if !containsDigit(fieldValue) {
-			return fmt.Sprintf("Field '%s' must contain at least one digit", fieldName)
+			return fmt.Sprintf("%s must contain at least one digit", Subject(fieldName))
 		}
 		if !containsSpecialCharacter(fieldValue) {
-			return fmt.Sprintf("Field '%s' must contain at least one special character", fieldName)
+			return fmt.Sprintf("%s must contain at least one special character", Subject(fieldName))
 		}
 	default:
 		pattern = strings.TrimPrefix(opt, "match:")
@@ -3,32 +3,48 @@ package enforcements
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 func HandleMaxStr(fieldValue, fieldName, opt string) string {
-	opt = ExtractNumber(opt)
+	opt = strings.TrimPrefix(opt, "max:")
 
 	minVal, err := strconv.Atoi(opt)
 	if err != nil {
-		return fmt.Sprintf("Invalid max value for field '%s'", fieldName)
+		return fmt.Sprintf("Invalid max value%s", ForField(fieldName))
 	}
 
 	if len(fieldValue) > minVal {
-		return fmt.Sprintf("Field '%s' must be at most %d characters long", fieldName, minVal)
+		return fmt.Sprintf("%s must be at most %d characters long", Subject(fieldName), minVal)
 	}
 	return ""
 }
 
 func HandleMaxInt(fieldValue int64, fieldName, opt string) string {
-	opt = ExtractNumber(opt)
+	opt = strings.TrimPrefix(opt, "max:")
 
 	minVal, err := strconv.Atoi(opt)
 	if err != nil {
-		return fmt.Sprintf("Invalid max value for field '%s'", fieldName)
+		return fmt.Sprintf("Invalid max value%s", ForField(fieldName))
 	}
 
 	if int(fieldValue) > minVal {
-		return fmt.Sprintf("Field '%s' must be at most %d", fieldName, minVal)
+		return fmt.Sprintf("%s must be at most %d", Subject(fieldName), minVal)
+	}
+
+	return ""
+}
+
+func HandleMaxFloat(fieldValue float64, fieldName, opt string) string {
+	param := strings.TrimPrefix(opt, "max:")
+
+	maxVal, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return fmt.Sprintf("Invalid max value%s", ForField(fieldName))
+	}
+
+	if fieldValue > maxVal {
+		return fmt.Sprintf("%s must be at most %s", Subject(fieldName), param)
 	}
 
 	return ""
@@ -3,32 +3,48 @@ package enforcements
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 func HandleMinStr(fieldValue, fieldName, opt string) string {
-	opt = ExtractNumber(opt)
+	opt = strings.TrimPrefix(opt, "min:")
 
 	minVal, err := strconv.Atoi(opt)
 	if err != nil {
-		return fmt.Sprintf("Invalid minimum value for field '%s'", fieldName)
+		return fmt.Sprintf("Invalid minimum value%s", ForField(fieldName))
 	}
 
 	if len(fieldValue) < minVal {
-		return fmt.Sprintf("Field '%s' must be at least %d characters long", fieldName, minVal)
+		return fmt.Sprintf("%s must be at least %d characters long", Subject(fieldName), minVal)
 	}
 	return ""
 }
 
 func HandleMinInt(fieldValue int64, fieldName, opt string) string {
-	opt = ExtractNumber(opt)
+	opt = strings.TrimPrefix(opt, "min:")
 
 	minVal, err := strconv.Atoi(opt)
 	if err != nil {
-		return fmt.Sprintf("Invalid minimum value for field '%s'", fieldName)
+		return fmt.Sprintf("Invalid minimum value%s", ForField(fieldName))
 	}
 
 	if int(fieldValue) < minVal {
-		return fmt.Sprintf("Field '%s' must be at least %d", fieldName, minVal)
+		return fmt.Sprintf("%s must be at least %d", Subject(fieldName), minVal)
+	}
+
+	return ""
+}
+
+func HandleMinFloat(fieldValue float64, fieldName, opt string) string {
+	param := strings.TrimPrefix(opt, "min:")
+
+	minVal, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return fmt.Sprintf("Invalid minimum value%s", ForField(fieldName))
+	}
+
+	if fieldValue < minVal {
+		return fmt.Sprintf("%s must be at least %s", Subject(fieldName), param)
 	}
 
 	return ""
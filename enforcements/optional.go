@@ -0,0 +1,17 @@
+package enforcements
+
+// IsOptional reports whether the rules of a field are skipped when it is empty.
+// That is the case for fields marked `optional` or `omitempty`, or every field
+// when OmitEmpty is set for the call, unless the field is `required`.
+func IsOptional(enforceOpts []string, settings Settings) bool {
+	optional := settings.OmitEmpty
+	for _, opt := range enforceOpts {
+		switch opt {
+		case "required":
+			return false
+		case "optional", "omitempty":
+			optional = true
+		}
+	}
+	return optional
+}
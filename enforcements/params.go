@@ -0,0 +1,279 @@
+package enforcements
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// paramParsers check the parameters of the built-in enforcements by rule name.
+// They read a parameter the way the handler does, whatever the type of the
+// value it is used on.
+var paramParsers = map[string]func(param string) error{
+	"between":     func(param string) error { return parseBounds(param, 2) },
+	"min":         func(param string) error { return parseBounds(param, 1) },
+	"max":         func(param string) error { return parseBounds(param, 1) },
+	"wordCount":   parseWordCount,
+	"match":       parseMatch,
+	"geohash":     parseGeohashPrecision,
+	"within":      parseWithin,
+	"phone":       parseCountryParam,
+	"postal":      parseCountryParam,
+	"country":     parseCountryFormat,
+	"email":       func(param string) error { _, err := parseEmailOptions(param); return err },
+	"base64":      func(param string) error { _, err := parseMaxDecoded(param); return err },
+	"base64url":   func(param string) error { _, err := parseMaxDecoded(param); return err },
+	"hex":         func(param string) error { _, err := parseMaxDecoded(param); return err },
+	"json":        parseJSONTopLevel,
+	"before":      func(param string) error { _, err := parseTimeValue(param, time.Now()); return err },
+	"after":       func(param string) error { _, err := parseTimeValue(param, time.Now()); return err },
+	"betweenTime": parseTimeRange,
+	"datetime":    func(param string) error { _, err := parseDatetimeParams(param); return err },
+	"minAge":      parseAgeLimit,
+	"maxAge":      parseAgeLimit,
+	"cron":        func(param string) error { _, err := parseMinInterval(param); return err },
+	"eqfield":     parseEqField,
+}
+
+// ParseParam checks the parameter of a built-in enforcement, the part of the
+// option after `name:`, so mistakes are found when a tag is compiled. Rules
+// without parameters accept any.
+func ParseParam(rule, param string) error {
+	if parse, ok := paramParsers[rule]; ok {
+		return parse(param)
+	}
+	return nil
+}
+
+// CheckParamFor reports what ParseParam can't know about the parameter of a
+// built-in enforcement: whether values of type t can be compared with it, such
+// as `min:1.5` on an int, and whether the sibling fields it names exist.
+// sibling is nil outside of structs.
+func CheckParamFor(rule, param string, t reflect.Type, sibling SiblingLookup) error {
+	var err error
+	switch rule {
+	case "between", "min", "max":
+		err = checkBounds(param, t)
+	case "enum":
+		err = checkValues(param, t, true)
+	case "exclude":
+		err = checkValues(param, t, false)
+	case "before", "after", "betweenTime":
+		if t != timeType {
+			return fmt.Errorf("rule '%s' needs a time.Time, got %s", rule, t)
+		}
+	case "minAge", "maxAge":
+		if t != timeType && t.Kind() != reflect.String {
+			return fmt.Errorf("rule '%s' needs a time.Time or a date string, got %s", rule, t)
+		}
+	case "phone", "postal":
+		_, err = resolveCountry(rule+":"+param, rule, sibling)
+	case "datetime":
+		err = checkDatetimeTarget(param, sibling)
+	case "eqfield":
+		err = checkEqField(param, t, sibling)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid parameter for rule '%s': %w", rule, err)
+	}
+	return nil
+}
+
+// parseBounds checks the `n` comma separated bounds of `between`, `min` and
+// `max`, which are numbers or durations depending on the field
+func parseBounds(param string, n int) error {
+	bounds := strings.Split(param, ",")
+	if len(bounds) != n {
+		return fmt.Errorf("%d values are required", n)
+	}
+	for _, bound := range bounds {
+		_, numberErr := strconv.ParseFloat(bound, 64)
+		_, durationErr := time.ParseDuration(bound)
+		if numberErr != nil && durationErr != nil {
+			return fmt.Errorf("'%s' is not a number or a duration", bound)
+		}
+	}
+	return nil
+}
+
+// checkBounds checks the bounds against the field: durations for
+// time.Duration, decimals for floats and whole numbers for integers and the
+// lengths of strings
+func checkBounds(param string, t reflect.Type) error {
+	for _, bound := range strings.Split(param, ",") {
+		var err error
+		switch {
+		case t == DurationType:
+			_, err = time.ParseDuration(bound)
+		case IsFloatType(t.Kind()):
+			_, err = strconv.ParseFloat(bound, 64)
+		default:
+			_, err = strconv.Atoi(bound)
+		}
+		if err != nil {
+			return fmt.Errorf("'%s' can't be compared with a %s", bound, t)
+		}
+	}
+	return nil
+}
+
+// checkValues checks the values of `enum` and `exclude` against the field.
+// Any text is a valid value for strings, and only `enum` reads durations.
+func checkValues(param string, t reflect.Type, durations bool) error {
+	for _, value := range strings.Split(param, ",") {
+		var err error
+		switch {
+		case durations && t == DurationType:
+			_, err = time.ParseDuration(value)
+		case IsIntType(t.Kind()):
+			_, err = strconv.ParseInt(value, 10, 64)
+		case IsUintType(t.Kind()):
+			_, err = strconv.ParseUint(value, 10, 64)
+		case IsFloatType(t.Kind()):
+			_, err = strconv.ParseFloat(value, 64)
+		}
+		if err != nil {
+			return fmt.Errorf("'%s' can't be compared with a %s", value, t)
+		}
+	}
+	return nil
+}
+
+func parseWordCount(param string) error {
+	counts := strings.Split(param, ",")
+	if len(counts) != 2 {
+		return errors.New("a minimum and a maximum are required")
+	}
+	for _, count := range counts {
+		if _, err := strconv.Atoi(count); err != nil {
+			return fmt.Errorf("'%s' is not a whole number", count)
+		}
+	}
+	return nil
+}
+
+// parseMatch compiles the pattern of `match:`, skipping the named patterns
+func parseMatch(param string) error {
+	switch param {
+	case "email", "phone", "password":
+		return nil
+	}
+	_, err := regexp.Compile(param)
+	return err
+}
+
+func parseGeohashPrecision(param string) error {
+	if param == "" {
+		return nil
+	}
+	if p, err := strconv.Atoi(param); err != nil || p < 1 || p > 12 {
+		return fmt.Errorf("precision '%s' must be between 1 and 12", param)
+	}
+	return nil
+}
+
+func parseWithin(param string) error {
+	if _, ok := parseBoundingBox(param); !ok {
+		return fmt.Errorf("'%s' is not a bounding box or a registered region", param)
+	}
+	return nil
+}
+
+// parseCountryParam checks a literal country of `phone` and `postal`. A
+// `country=<Field>` is checked with the struct by CheckParamFor.
+func parseCountryParam(param string) error {
+	if param == "" || strings.HasPrefix(param, "country=") {
+		if param == "country=" {
+			return errors.New("country= needs a field name")
+		}
+		return nil
+	}
+	if _, ok := lookupCountryMeta(param); !ok {
+		return fmt.Errorf("unknown country '%s'", param)
+	}
+	return nil
+}
+
+func parseCountryFormat(param string) error {
+	switch param {
+	case "", "alpha2", "alpha3", "numeric":
+		return nil
+	}
+	return fmt.Errorf("unknown country code format '%s'", param)
+}
+
+func parseJSONTopLevel(param string) error {
+	if _, ok := jsonTopLevels[param]; !ok {
+		return fmt.Errorf("unknown top level type '%s'", param)
+	}
+	return nil
+}
+
+func parseTimeRange(param string) error {
+	times := strings.Split(param, ",")
+	if len(times) != 2 {
+		return errors.New("a start and an end are required")
+	}
+	for _, t := range times {
+		if _, err := parseTimeValue(t, time.Now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseAgeLimit(param string) error {
+	if limit, err := strconv.Atoi(param); err != nil || limit < 0 {
+		return fmt.Errorf("'%s' is not a number of years", param)
+	}
+	return nil
+}
+
+func parseEqField(param string) error {
+	if param == "" {
+		return errors.New("a field name is required")
+	}
+	return nil
+}
+
+// checkDatetimeTarget checks that the `into=` field of a datetime enforcement
+// is a time.Time that can be set
+func checkDatetimeTarget(param string, sibling SiblingLookup) error {
+	p, err := parseDatetimeParams(param)
+	if err != nil || p.into == "" {
+		return err
+	}
+	var target reflect.Value
+	ok := false
+	if sibling != nil {
+		target, ok = sibling(p.into)
+	}
+	if !ok || target.Type() != timeType || !target.CanSet() {
+		return fmt.Errorf("target '%s' is not a time.Time field that can be set", p.into)
+	}
+	return nil
+}
+
+// checkEqField checks that the field compared with exists and has the type of
+// the value
+func checkEqField(param string, t reflect.Type, sibling SiblingLookup) error {
+	if sibling == nil {
+		return fmt.Errorf("field '%s' is only available on structs", param)
+	}
+	other, ok := sibling(param)
+	if !ok || !other.CanInterface() {
+		return fmt.Errorf("field '%s' not found", param)
+	}
+	otherType := other.Type()
+	if otherType.Kind() == reflect.Ptr {
+		otherType = otherType.Elem()
+	}
+	if otherType != t {
+		return fmt.Errorf("field '%s' has a different type", param)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package enforcements
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseParam(t *testing.T) {
+	tests := []struct {
+		rule, param string
+		wantErr     bool
+	}{
+		{"between", "1,10", false},
+		{"between", "1.5,2.5", false},
+		{"between", "1s,1m", false},
+		{"between", "1", true},
+		{"between", "a,b", true},
+		{"min", "-5", false},
+		{"min", "abc", true},
+		{"max", "1,2", true},
+		{"wordCount", "1,5", false},
+		{"wordCount", "1.5,5", true},
+		{"match", `^\d+$`, false},
+		{"match", "email", false},
+		{"match", "(", true},
+		{"geohash", "", false},
+		{"geohash", "13", true},
+		{"within", "0,0,1,1", false},
+		{"within", "nowhere", true},
+		{"phone", "NP", false},
+		{"phone", "country=Country", false},
+		{"phone", "XX", true},
+		{"postal", "country=", true},
+		{"country", "alpha3", false},
+		{"country", "alpha4", true},
+		{"email", "mx,noDisposable", false},
+		{"email", "bogus", true},
+		{"hex", "maxDecoded=1KB", false},
+		{"base64", "maxDecoded=lots", true},
+		{"json", "array", false},
+		{"json", "string", true},
+		{"before", "timeNow-18_years", false},
+		{"after", "yesterday-ish", true},
+		{"betweenTime", "2024-01-01,timeNow", false},
+		{"betweenTime", "2024-01-01", true},
+		{"datetime", "date,into=At", false},
+		{"datetime", "date,bogus=1", true},
+		{"minAge", "18", false},
+		{"maxAge", "-1", true},
+		{"cron", "minInterval=5m", false},
+		{"cron", "every=5m", true},
+		{"eqfield", "", true},
+		{"latitude", "anything", false},
+	}
+	for _, tt := range tests {
+		if err := ParseParam(tt.rule, tt.param); (err != nil) != tt.wantErr {
+			t.Errorf("ParseParam(%q, %q) = %v, want error %v", tt.rule, tt.param, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckParamFor(t *testing.T) {
+	type form struct {
+		Password string
+		Count    int
+		Country  string
+		At       time.Time
+		at       time.Time
+	}
+	f := form{}
+	sibling := func(name string) (reflect.Value, bool) {
+		v := reflect.ValueOf(&f).Elem().FieldByName(name)
+		return v, v.IsValid()
+	}
+
+	tests := []struct {
+		rule, param string
+		value       interface{}
+		sibling     SiblingLookup
+		wantErr     bool
+	}{
+		{"min", "5", 0, nil, false},
+		{"min", "1.5", 0, nil, true},
+		{"min", "1.5", 0.0, nil, false},
+		{"min", "5", "", nil, false},
+		{"between", "1s,1m", time.Second, nil, false},
+		{"between", "1,60", time.Second, nil, true},
+		{"enum", "1,2", 0, nil, false},
+		{"enum", "a,b", 0, nil, true},
+		{"enum", "1s,2s", time.Second, nil, false},
+		{"enum", "a,b", "", nil, false},
+		{"exclude", "0.5", 0, nil, true},
+		{"exclude", "5", uint(0), nil, false},
+		{"before", "timeNow", time.Time{}, nil, false},
+		{"before", "timeNow", "", nil, true},
+		{"minAge", "18", "", nil, false},
+		{"minAge", "18", 0, nil, true},
+		{"eqfield", "Password", "", sibling, false},
+		{"eqfield", "Count", "", sibling, true},
+		{"eqfield", "Missing", "", sibling, true},
+		{"eqfield", "Password", "", nil, true},
+		{"phone", "country=Country", "", sibling, false},
+		{"phone", "country=Count", "", sibling, true},
+		{"postal", "NP", "", nil, false},
+		{"datetime", "date,into=At", "", sibling, false},
+		{"datetime", "date,into=at", "", sibling, true},
+		{"datetime", "date,into=Count", "", sibling, true},
+		{"datetime", "date,into=At", "", nil, true},
+		{"datetime", "date", "", nil, false},
+	}
+	for _, tt := range tests {
+		err := CheckParamFor(tt.rule, tt.param, reflect.TypeOf(tt.value), tt.sibling)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckParamFor(%q, %q, %T) = %v, want error %v", tt.rule, tt.param, tt.value, err, tt.wantErr)
+		}
+	}
+}
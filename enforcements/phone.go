@@ -0,0 +1,155 @@
+package enforcements
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed data/country_metadata.tsv
+var countryMetadataTable string
+
+var (
+	e164Pattern          = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+	genericPostalPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 \-]{1,9}$`)
+)
+
+// countryMeta holds the telephony and postal details of a single country
+type countryMeta struct {
+	callingCode string
+	trunkPrefix string
+	minLen      int
+	maxLen      int
+	postal      *regexp.Regexp
+}
+
+var (
+	countryMetaOnce sync.Once
+	countryMetaMap  map[string]countryMeta
+)
+
+func lookupCountryMeta(code string) (countryMeta, bool) {
+	countryMetaOnce.Do(func() {
+		countryMetaMap = map[string]countryMeta{}
+		for _, cols := range tableRows(countryMetadataTable) {
+			minLen, _ := strconv.Atoi(cols[3])
+			maxLen, _ := strconv.Atoi(cols[4])
+			meta := countryMeta{callingCode: cols[1], trunkPrefix: cols[2], minLen: minLen, maxLen: maxLen}
+			if cols[5] != "" {
+				meta.postal = regexp.MustCompile(cols[5])
+			}
+			countryMetaMap[cols[0]] = meta
+		}
+	})
+	meta, ok := countryMetaMap[strings.ToUpper(code)]
+	return meta, ok
+}
+
+// resolveCountry reads the country of an enforcement such as `phone:NP` or
+// `phone:country=CountryCode`, where the latter takes it from a sibling field
+func resolveCountry(opt, prefix string, sibling SiblingLookup) (string, error) {
+	param := strings.TrimPrefix(strings.TrimPrefix(opt, prefix), ":")
+	if param == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(param, "country=") {
+		if _, ok := lookupCountryMeta(param); !ok {
+			return "", fmt.Errorf("unknown country '%s'", param)
+		}
+		return strings.ToUpper(param), nil
+	}
+
+	siblingName := strings.TrimPrefix(param, "country=")
+	if sibling == nil {
+		return "", fmt.Errorf("country field '%s' is only available on structs", siblingName)
+	}
+	siblingValue, ok := sibling(siblingName)
+	if !ok || siblingValue.Kind() != reflect.String {
+		return "", fmt.Errorf("country field '%s' not found", siblingName)
+	}
+	return strings.ToUpper(strings.TrimSpace(siblingValue.String())), nil
+}
+
+func HandlePhone(fieldValue, fieldName, opt string, sibling SiblingLookup) string {
+	country, err := resolveCountry(opt, "phone", sibling)
+	if err != nil {
+		return fmt.Sprintf("Invalid phone enforcement%s: %s", ForField(fieldName), err)
+	}
+
+	if !e164Pattern.MatchString(fieldValue) {
+		return fmt.Sprintf("%s must be a phone number in E.164 format", Subject(fieldName))
+	}
+	meta, ok := lookupCountryMeta(country)
+	if !ok {
+		return ""
+	}
+	national := strings.TrimPrefix(fieldValue[1:], meta.callingCode)
+	if national == fieldValue[1:] || len(national) < meta.minLen || len(national) > meta.maxLen {
+		return fmt.Sprintf("%s must be a valid phone number for %s", Subject(fieldName), country)
+	}
+	return ""
+}
+
+func HandlePostal(fieldValue, fieldName, opt string, sibling SiblingLookup) string {
+	country, err := resolveCountry(opt, "postal", sibling)
+	if err != nil {
+		return fmt.Sprintf("Invalid postal enforcement%s: %s", ForField(fieldName), err)
+	}
+
+	meta, ok := lookupCountryMeta(country)
+	if !ok {
+		if !genericPostalPattern.MatchString(fieldValue) {
+			return fmt.Sprintf("%s must be a valid postal code", Subject(fieldName))
+		}
+		return ""
+	}
+	if meta.postal == nil {
+		if fieldValue == "" {
+			return ""
+		}
+		return fmt.Sprintf("%s must be empty because %s does not use postal codes", Subject(fieldName), country)
+	}
+	if !meta.postal.MatchString(strings.ToUpper(strings.TrimSpace(fieldValue))) {
+		return fmt.Sprintf("%s must be a valid postal code for %s", Subject(fieldName), country)
+	}
+	return ""
+}
+
+// ToE164 converts a phone number to canonical E.164 form. Numbers in national
+// format need a country so that the trunk prefix can be replaced by the calling code.
+func ToE164(number, country string) (string, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '(', ')', '.', '/':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(number))
+
+	switch {
+	case strings.HasPrefix(cleaned, "+"):
+	case strings.HasPrefix(cleaned, "00"):
+		cleaned = "+" + cleaned[2:]
+	default:
+		meta, ok := lookupCountryMeta(country)
+		if !ok {
+			return "", errors.New("country is required for numbers without a calling code")
+		}
+		national := cleaned
+		if meta.trunkPrefix != "" && strings.HasPrefix(national, meta.trunkPrefix) &&
+			len(national)-len(meta.trunkPrefix) >= meta.minLen {
+			national = national[len(meta.trunkPrefix):]
+		}
+		cleaned = "+" + meta.callingCode + national
+	}
+
+	if !e164Pattern.MatchString(cleaned) {
+		return "", fmt.Errorf("'%s' cannot be converted to E.164", number)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,101 @@
+package enforcements
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHandlePhone(t *testing.T) {
+	tests := []struct {
+		value   string
+		opt     string
+		wantErr bool
+	}{
+		{"+9779841234567", "phone", false},
+		{"+14155552671", "phone", false},
+		{"+9779841234567", "phone:NP", false},
+		{"+9779841234567", "phone:np", false},
+		{"+14155552671", "phone:NP", true},
+		{"+977984", "phone:NP", true},
+		{"+4930123456", "phone:DE", false},
+		{"9841234567", "phone", true},
+		{"+0123456789", "phone", true},
+		{"+1234567890123456", "phone", true},
+		{"+977 984 1234567", "phone", true},
+		{"+9779841234567", "phone:ZZ", true},
+	}
+	for _, tt := range tests {
+		if got := HandlePhone(tt.value, "Phone", tt.opt, nil); (got != "") != tt.wantErr {
+			t.Errorf("HandlePhone(%q, %q) = %q, want error %v", tt.value, tt.opt, got, tt.wantErr)
+		}
+	}
+}
+
+func TestHandlePhoneSiblingCountry(t *testing.T) {
+	req := struct{ Country string }{Country: "np"}
+	sibling := func(name string) (reflect.Value, bool) {
+		f := reflect.ValueOf(req).FieldByName(name)
+		return f, f.IsValid()
+	}
+	if got := HandlePhone("+9779841234567", "Phone", "phone:country=Country", sibling); got != "" {
+		t.Errorf("HandlePhone with a sibling country = %q", got)
+	}
+	if got := HandlePhone("+14155552671", "Phone", "phone:country=Country", sibling); got == "" {
+		t.Error("HandlePhone accepted a US number for a Nepali sibling country")
+	}
+	if got := HandlePhone("+9779841234567", "Phone", "phone:country=Missing", sibling); got == "" {
+		t.Error("HandlePhone accepted a missing sibling field")
+	}
+	if got := HandlePhone("+9779841234567", "Phone", "phone:country=Country", nil); got == "" {
+		t.Error("HandlePhone accepted a sibling country without a struct")
+	}
+}
+
+func TestToE164(t *testing.T) {
+	tests := []struct {
+		number, country string
+		want            string
+		wantErr         bool
+	}{
+		{"+977 984-1234567", "", "+9779841234567", false},
+		{"00977 9841234567", "", "+9779841234567", false},
+		{"(415) 555-2671", "US", "+14155552671", false},
+		{"1 415 555 2671", "US", "+14155552671", false},
+		{"030 123456", "DE", "+4930123456", false},
+		{"07911 123456", "GB", "+447911123456", false},
+		{"9841234567", "NP", "+9779841234567", false},
+		{"9841234567", "", "", true},
+		{"+0123", "", "", true},
+		{"phone", "NP", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ToE164(tt.number, tt.country)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ToE164(%q, %q) = %q, %v, want %q", tt.number, tt.country, got, err, tt.want)
+		}
+	}
+}
+
+func TestHandlePostal(t *testing.T) {
+	tests := []struct {
+		value   string
+		opt     string
+		wantErr bool
+	}{
+		{"44600", "postal:NP", false},
+		{"4460", "postal:NP", true},
+		{"94105-1234", "postal:US", false},
+		{"SW1A 1AA", "postal:GB", false},
+		{"sw1a1aa", "postal:GB", false},
+		{"SW1A", "postal:GB", true},
+		{"", "postal:AE", false},
+		{"12345", "postal:AE", true},
+		{"K1A 0B1", "postal", false},
+		{"!", "postal", true},
+	}
+	for _, tt := range tests {
+		if got := HandlePostal(tt.value, "Postal", tt.opt, nil); (got != "") != tt.wantErr {
+			t.Errorf("HandlePostal(%q, %q) = %q, want error %v", tt.value, tt.opt, got, tt.wantErr)
+		}
+	}
+}
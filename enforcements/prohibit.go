@@ -0,0 +1,57 @@
+package enforcements
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Prohibit modes select what happens when a client sends a `prohibit` field
+const (
+	// ProhibitStrip resets the field to its zero value without telling the client
+	ProhibitStrip = "strip"
+	// ProhibitReport keeps the value and reports a FieldError
+	ProhibitReport = "report"
+	// ProhibitBoth resets the field and reports a FieldError
+	ProhibitBoth = "both"
+)
+
+// StripAudit is called with the dotted JSON path of every prohibited field whose
+// value was stripped, e.g. to monitor mass assignment attempts
+type StripAudit func(ctx context.Context, path string)
+
+// prohibitMode returns the mode of the `prohibit` or `prohibit:<mode>` option in
+// a tag. A bare `prohibit` uses the mode in settings, which defaults to strip.
+func prohibitMode(tagValue string, settings Settings) (mode string, ok bool, err error) {
+	for _, opt := range strings.Split(tagValue, " ") {
+		name, param, _ := strings.Cut(opt, ":")
+		if name != "prohibit" {
+			continue
+		}
+		if param == "" {
+			param = settings.ProhibitMode
+		}
+		switch param {
+		case "":
+			return ProhibitStrip, true, nil
+		case ProhibitStrip, ProhibitReport, ProhibitBoth:
+			return param, true, nil
+		}
+		return "", false, fmt.Errorf("unknown prohibit mode '%s'", param)
+	}
+	return "", false, nil
+}
+
+func prohibitedError(label string, loc fieldLocation) *FieldError {
+	name := label
+	if name == "" {
+		name = loc.path
+	}
+	return &FieldError{
+		Field:   loc.path,
+		Path:    loc.key,
+		Pointer: loc.pointer,
+		Rule:    "prohibit",
+		Message: fmt.Sprintf("%s is not allowed", Subject(name)),
+	}
+}
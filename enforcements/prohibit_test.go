@@ -0,0 +1,81 @@
+package enforcements
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestProhibitMode(t *testing.T) {
+	tests := []struct {
+		tag         string
+		defaultMode string
+		wantMode    string
+		wantFound   bool
+		wantErr     bool
+	}{
+		{"required", "", "", false, false},
+		{"prohibit", "", ProhibitStrip, true, false},
+		{"prohibit", ProhibitReport, ProhibitReport, true, false},
+		{"prohibit:both", ProhibitReport, ProhibitBoth, true, false},
+		{"optional prohibit:strip", "", ProhibitStrip, true, false},
+		{"prohibit:drop", "", "", false, true},
+		{"prohibit", "drop", "", false, true},
+	}
+	for _, tt := range tests {
+		mode, found, err := prohibitMode(tt.tag, Settings{ProhibitMode: tt.defaultMode})
+		if mode != tt.wantMode || found != tt.wantFound || (err != nil) != tt.wantErr {
+			t.Errorf("prohibitMode(%q, %q) = %q, %v, %v, want %q, %v, error %v",
+				tt.tag, tt.defaultMode, mode, found, err, tt.wantMode, tt.wantFound, tt.wantErr)
+		}
+	}
+}
+
+func TestApplyProhibits(t *testing.T) {
+	type account struct {
+		Role    string `json:"role" enforce:"prohibit default:user"`
+		Admin   bool   `json:"admin" enforce:"prohibit:report"`
+		Credits int    `json:"credits" enforce:"prohibit:both"`
+		Name    string `json:"name"`
+	}
+
+	var stripped []string
+	settings := Settings{OnStrip: func(ctx context.Context, path string) { stripped = append(stripped, path) }}
+	req := account{Role: "admin", Admin: true, Credits: 100, Name: "a"}
+	reports, err := ApplyDefaultsAndProhibits(&req, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := account{Role: "user", Admin: true, Credits: 0, Name: "a"}
+	if req != want {
+		t.Errorf("req = %+v, want %+v", req, want)
+	}
+	var reported []string
+	for _, r := range reports {
+		reported = append(reported, r.Path)
+	}
+	if !reflect.DeepEqual(reported, []string{"admin", "credits"}) {
+		t.Errorf("reported = %v, want admin and credits", reported)
+	}
+	if !reflect.DeepEqual(stripped, []string{"role", "credits"}) {
+		t.Errorf("stripped = %v, want role and credits", stripped)
+	}
+
+	// Fields the client did not send are left alone
+	req = account{}
+	if reports, _ := ApplyDefaultsAndProhibits(&req, Settings{}); len(reports) != 0 || req.Role != "user" {
+		t.Errorf("unsent fields: reports = %v, req = %+v", reports, req)
+	}
+}
+
+func TestApplyProhibitsUnknownMode(t *testing.T) {
+	req := struct {
+		Role string `enforce:"prohibit:drop"`
+	}{Role: "admin"}
+	var schemaErr *SchemaError
+	if _, err := ApplyDefaultsAndProhibits(&req, Settings{}); !errors.As(err, &schemaErr) || schemaErr.Rule != "prohibit" {
+		t.Errorf("ApplyDefaultsAndProhibits() = %v, want a prohibit SchemaError", err)
+	}
+}
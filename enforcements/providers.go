@@ -0,0 +1,136 @@
+package enforcements
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultProvider computes a default at ApplyDefaults time for `default:@name`
+// or `default:@name(arg)`. The value is assigned directly when its type matches
+// the field, otherwise it is converted like a default written in the tag.
+type DefaultProvider func(ctx context.Context, arg string) (interface{}, error)
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]DefaultProvider{
+		"uuid": uuidProvider,
+		"env":  envProvider,
+	}
+)
+
+// registrations counts the providers and regions registered so far. Tags are
+// checked against them when a struct is compiled, so a compiled struct is
+// compiled again once this changes.
+var registrations atomic.Uint64
+
+// Registrations returns the number of registered providers and regions
+func Registrations() uint64 {
+	return registrations.Load()
+}
+
+// RegisterDefaultProvider makes a provider available as `default:@<name>`.
+// Names may contain dots, e.g. `tenant.plan`. It panics when provider is nil.
+func RegisterDefaultProvider(name string, provider DefaultProvider) {
+	if provider == nil {
+		panic(fmt.Sprintf("enforcer: default provider '@%s' is nil", name))
+	}
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[name] = provider
+	registrations.Add(1)
+}
+
+func lookupProvider(name string) (DefaultProvider, bool) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	provider, ok := providers[name]
+	return provider, ok
+}
+
+// uuidProvider generates a random (version 4) UUID
+func uuidProvider(ctx context.Context, arg string) (interface{}, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// envProvider reads the environment variable named by arg, e.g. `@env(REGION)`
+func envProvider(ctx context.Context, arg string) (interface{}, error) {
+	value, ok := os.LookupEnv(arg)
+	if !ok {
+		return nil, fmt.Errorf("environment variable '%s' is not set", arg)
+	}
+	return value, nil
+}
+
+// providerError is a failure of a registered provider while defaults are
+// applied. It comes from the environment or the request, not from the tag.
+type providerError struct {
+	err error
+}
+
+func (e *providerError) Error() string {
+	return e.err.Error()
+}
+
+func (e *providerError) Unwrap() error {
+	return e.err
+}
+
+// parseProvider splits `@name(arg)` into the provider name and its argument
+func parseProvider(defaultValue string) (name, arg string) {
+	name = strings.TrimPrefix(defaultValue, "@")
+	if open := strings.IndexByte(name, '('); open >= 0 && strings.HasSuffix(name, ")") {
+		name, arg = name[:open], name[open+1:len(name)-1]
+	}
+	return name, arg
+}
+
+// CheckProvidedDefault reports a default like `@uuid` whose provider is not
+// registered, so misspelled providers are caught when a struct is compiled
+func CheckProvidedDefault(defaultValue string) error {
+	name, _ := parseProvider(defaultValue)
+	if _, ok := lookupProvider(name); !ok {
+		return fmt.Errorf("default provider '@%s' is not registered", name)
+	}
+	return nil
+}
+
+// provideDefault resolves `@name` or `@name(arg)` through the provider registry.
+// Failures of the provider, or of converting what it returned, are returned as
+// a *providerError.
+func provideDefault(fieldValue reflect.Value, defaultValue string, settings Settings) error {
+	name, arg := parseProvider(defaultValue)
+	provider, ok := lookupProvider(name)
+	if !ok {
+		return fmt.Errorf("default provider '@%s' is not registered", name)
+	}
+	provided, err := provider(settings.CallContext(), arg)
+	if err != nil {
+		return &providerError{fmt.Errorf("default provider '@%s' failed: %w", name, err)}
+	}
+
+	pv := reflect.ValueOf(provided)
+	if pv.IsValid() && pv.Type().AssignableTo(fieldValue.Type()) {
+		fieldValue.Set(pv)
+		return nil
+	}
+	text, ok := provided.(string)
+	if !ok {
+		text = fmt.Sprint(provided)
+	}
+	if err := setLiteralDefault(fieldValue, text, settings); err != nil {
+		return &providerError{fmt.Errorf("default provider '@%s' returned %q: %w", name, text, err)}
+	}
+	return nil
+}
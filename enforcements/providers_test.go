@@ -0,0 +1,100 @@
+package enforcements
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestParseProvider(t *testing.T) {
+	tests := []struct {
+		value, name, arg string
+	}{
+		{"@uuid", "uuid", ""},
+		{"@env(REGION)", "env", "REGION"},
+		{"@tenant.plan", "tenant.plan", ""},
+		{"@env(", "env(", ""},
+	}
+	for _, tt := range tests {
+		if name, arg := parseProvider(tt.value); name != tt.name || arg != tt.arg {
+			t.Errorf("parseProvider(%q) = %q, %q, want %q, %q", tt.value, name, arg, tt.name, tt.arg)
+		}
+	}
+}
+
+func TestProvideDefault(t *testing.T) {
+	RegisterDefaultProvider("test.plan", func(ctx context.Context, arg string) (interface{}, error) {
+		return "pro", nil
+	})
+	RegisterDefaultProvider("test.count", func(ctx context.Context, arg string) (interface{}, error) {
+		return arg, nil
+	})
+	RegisterDefaultProvider("test.fail", func(ctx context.Context, arg string) (interface{}, error) {
+		return nil, errors.New("unavailable")
+	})
+	t.Setenv("ENFORCER_TEST_REGION", "ap-south-1")
+
+	tests := []struct {
+		value        string
+		field        interface{}
+		want         interface{}
+		wantProvider bool
+		wantErr      bool
+	}{
+		{"@test.plan", "", "pro", false, false},
+		{"@test.count(42)", 0, 42, false, false},
+		{"@env(ENFORCER_TEST_REGION)", "", "ap-south-1", false, false},
+		{"@test.count(many)", 0, 0, true, true},
+		{"@test.fail", "", "", true, true},
+		{"@env(ENFORCER_TEST_UNSET)", "", "", true, true},
+		{"@test.missing", "", "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			field := reflect.New(reflect.TypeOf(tt.field)).Elem()
+			err := SetDefault(field, tt.value, Settings{})
+			var failed *providerError
+			if (err != nil) != tt.wantErr || errors.As(err, &failed) != tt.wantProvider {
+				t.Fatalf("SetDefault(%q) = %v, want error %v from a provider %v", tt.value, err, tt.wantErr, tt.wantProvider)
+			}
+			if got := field.Interface(); got != tt.want {
+				t.Errorf("SetDefault(%q) set %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUUIDProvider(t *testing.T) {
+	uuid, err := uuidProvider(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(uuid.(string)) {
+		t.Errorf("uuidProvider = %q, want a version 4 UUID", uuid)
+	}
+}
+
+func TestRegisterDefaultProvider(t *testing.T) {
+	if err := CheckProvidedDefault("@test.late"); err == nil {
+		t.Fatal("CheckProvidedDefault accepted an unregistered provider")
+	}
+	before := Registrations()
+	RegisterDefaultProvider("test.late", func(ctx context.Context, arg string) (interface{}, error) {
+		return "late", nil
+	})
+	if Registrations() == before {
+		t.Error("registering a provider did not change Registrations")
+	}
+	if err := CheckProvidedDefault("@test.late(arg)"); err != nil {
+		t.Errorf("CheckProvidedDefault = %v after registering", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a nil provider did not panic")
+		}
+	}()
+	RegisterDefaultProvider("test.nil", nil)
+}
@@ -0,0 +1,52 @@
+package enforcements
+
+import "testing"
+
+func TestPunycodeEncode(t *testing.T) {
+	// Vectors from RFC 3492 section 7.1 and common IDN labels
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"bücher", "bcher-kva"},
+		{"münchen", "mnchen-3ya"},
+		{"ü", "tda"},
+		{"ليهمابتكلموشعربي؟", "egbpdaj6bu4bxfgehfvwxn"},
+		{"他们为什么不说中文", "ihqwcrb4cv8a8dqg056pqjye"},
+		{"ひとつ屋根の下2", "2-u9tlzr9756bt3uc0v"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := punycodeEncode(tt.input)
+			if err != nil {
+				t.Fatalf("punycodeEncode(%q) error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("punycodeEncode(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToASCIIDomain(t *testing.T) {
+	tests := []struct {
+		domain string
+		want   string
+	}{
+		{"example.com", "example.com"},
+		{"Example.COM", "example.com"},
+		{"münchen.de", "xn--mnchen-3ya.de"},
+		{"bücher。example", "xn--bcher-kva.example"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			got, err := toASCIIDomain(tt.domain)
+			if err != nil {
+				t.Fatalf("toASCIIDomain(%q) error: %v", tt.domain, err)
+			}
+			if got != tt.want {
+				t.Errorf("toASCIIDomain(%q) = %q, want %q", tt.domain, got, tt.want)
+			}
+		})
+	}
+}
@@ -6,7 +6,17 @@ import (
 )
 
 func HandleRequired(fieldValue reflect.Value, fieldName string) string {
-	if IsEmpty(fieldValue)  {
+	return HandleRequiredPresence(!IsEmpty(fieldValue), fieldName)
+}
+
+// HandleRequiredPresence reports a missing field when provided is false, for
+// callers that know whether the client sent the field (see Settings.Provided)
+func HandleRequiredPresence(provided bool, fieldName string) string {
+	switch {
+	case provided:
+	case fieldName == "":
+		return "Value is required"
+	default:
 		return fmt.Sprintf("Required field '%s' is not provided", fieldName)
 	}
 
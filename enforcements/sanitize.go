@@ -0,0 +1,87 @@
+package enforcements
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ApplySanitizers rewrites field values into their canonical form before they
+// are validated. Values that cannot be converted are left untouched so that the
+// matching enforcement can report them.
+func ApplySanitizers(v interface{}) error {
+	return ApplySanitizersWith(v, Settings{})
+}
+
+// ApplySanitizersWith applies sanitizers reading the tag named in settings.
+// Nested structs, also as the elements of a collection, are sanitized with
+// their own tags. Mistakes in a tag, such as an unknown country, are returned
+// as a *SchemaError.
+func ApplySanitizersWith(v interface{}, settings Settings) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("pointer to struct expected, got %T", v)
+	}
+
+	rv = rv.Elem()
+
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("pointer to struct expected, got pointer to %T", v)
+	}
+
+	return applySanitizers(rv, settings)
+}
+
+func applySanitizers(rv reflect.Value, settings Settings) error {
+	sibling := func(name string) (reflect.Value, bool) {
+		f := rv.FieldByName(name)
+		return f, f.IsValid()
+	}
+
+	for i := 0; i < rv.NumField(); i++ {
+		fieldValue := rv.Field(i)
+		fieldType := rv.Type().Field(i)
+
+		// Like defaults, only the promoted fields of an unexported embedded
+		// struct can be set
+		if !fieldValue.CanSet() {
+			if nested, ok := nestedStruct(fieldValue); ok && fieldType.Anonymous {
+				if err := applySanitizers(nested, settings); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		for _, opt := range strings.Split(settings.Tag(fieldType), " ") {
+			if name, _, _ := strings.Cut(opt, ":"); name != "toE164" {
+				continue
+			}
+			country, err := resolveCountry(opt, "toE164", sibling)
+			if err == nil && fieldValue.Kind() != reflect.String {
+				err = fmt.Errorf("unsupported type %s", fieldType.Type)
+			}
+			if err != nil {
+				return &SchemaError{Struct: rv.Type().Name(), Field: fieldType.Name, Rule: "toE164", Message: err.Error()}
+			}
+			if number, err := ToE164(fieldValue.String(), country); err == nil {
+				fieldValue.SetString(number)
+			}
+		}
+
+		if nested, ok := nestedStruct(fieldValue); ok {
+			if err := applySanitizers(nested, settings); err != nil {
+				return err
+			}
+			continue
+		}
+		err := eachElement(fieldValue, func(elem reflect.Value, _ string) error {
+			return applySanitizers(elem, settings)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
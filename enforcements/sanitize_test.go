@@ -0,0 +1,72 @@
+package enforcements
+
+import (
+	"errors"
+	"testing"
+)
+
+type sanitizeContact struct {
+	Country string
+	Phone   string `enforce:"toE164:country=Country"`
+}
+
+type sanitizeOwner struct {
+	Phone string `enforce:"toE164:US"`
+}
+
+type sanitizeCompany struct {
+	Phone    string `enforce:"toE164:NP"`
+	Owner    *sanitizeOwner
+	Contacts []sanitizeContact
+	Branches map[string]sanitizeContact
+}
+
+func TestApplySanitizersNested(t *testing.T) {
+	company := sanitizeCompany{
+		Phone:    "9841234567",
+		Owner:    &sanitizeOwner{Phone: "(415) 555-2671"},
+		Contacts: []sanitizeContact{{Country: "GB", Phone: "07911 123456"}},
+		Branches: map[string]sanitizeContact{"berlin": {Country: "DE", Phone: "030 123456"}},
+	}
+	if err := ApplySanitizers(&company); err != nil {
+		t.Fatal(err)
+	}
+
+	got := []string{company.Phone, company.Owner.Phone, company.Contacts[0].Phone, company.Branches["berlin"].Phone}
+	want := []string{"+9779841234567", "+14155552671", "+447911123456", "+4930123456"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("phone %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestApplySanitizersSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+	}{
+		{"unknown country", &struct {
+			Phone string `enforce:"toE164:XX"`
+		}{Phone: "9841234567"}},
+		{"missing sibling", &struct {
+			Phone string `enforce:"toE164:country=Country"`
+		}{Phone: "9841234567"}},
+		{"not a string", &struct {
+			Phone int `enforce:"toE164:NP"`
+		}{}},
+		{"nested unknown country", &struct {
+			Owner struct {
+				Phone string `enforce:"toE164:XX"`
+			}
+		}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schemaErr *SchemaError
+			if err := ApplySanitizers(tt.v); !errors.As(err, &schemaErr) || schemaErr.Rule != "toE164" {
+				t.Errorf("ApplySanitizers() = %v, want a toE164 SchemaError", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package enforcements
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Settings carries per call configuration into defaults and enforcements.
+// Zero fields fall back to the package wide values.
+type Settings struct {
+	Clock    Clock
+	Location *time.Location
+	// Context is handed to default providers
+	Context context.Context
+	// PresentKeys holds the lower cased JSON keys sent by the client, with
+	// nested objects as dotted paths. When nil, presence is guessed from values.
+	PresentKeys map[string]bool
+	// ProhibitMode is used by a bare `prohibit`, which strips when it is empty
+	ProhibitMode string
+	// OnStrip is called for every prohibited field that gets stripped
+	OnStrip StripAudit
+	// OmitEmpty skips the rules of every empty field that is not `required`
+	OmitEmpty bool
+	// TagName is the struct tag holding the rules, `enforce` when empty
+	TagName string
+	// FieldName names fields in messages and error paths, the Go field name
+	// when nil
+	FieldName func(field reflect.StructField) string
+}
+
+// Tag returns the rules of a field from the configured struct tag
+func (s Settings) Tag(field reflect.StructField) string {
+	return field.Tag.Get(s.TagKey())
+}
+
+// TagKey returns the name of the struct tag holding the rules
+func (s Settings) TagKey() string {
+	if s.TagName != "" {
+		return s.TagName
+	}
+	return "enforce"
+}
+
+// NameOf returns the name a field is given in messages
+func (s Settings) NameOf(field reflect.StructField) string {
+	if s.FieldName != nil {
+		if name := s.FieldName(field); name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// CallContext returns the context of the call, context.Background() when none
+// was given
+func (s Settings) CallContext() context.Context {
+	if s.Context != nil {
+		return s.Context
+	}
+	return context.Background()
+}
+
+// Now returns the current time of the configured clock in the configured location
+func (s Settings) Now() time.Time {
+	timeMu.RLock()
+	clock, loc := timeClock, timeLocation
+	timeMu.RUnlock()
+
+	if s.Clock != nil {
+		clock = s.Clock
+	}
+	if s.Location != nil {
+		loc = s.Location
+	}
+	return clock.Now().In(loc)
+}
+
+// Provided reports whether the client sent a field. With PresentKeys the JSON
+// key decides, so an explicit 0 counts as provided. Without them a non-nil
+// pointer is provided even when it points to a zero value, while other fields
+// are provided when they are not empty.
+func (s Settings) Provided(key string, fieldValue reflect.Value) bool {
+	if s.PresentKeys != nil {
+		return key != "" && s.PresentKeys[strings.ToLower(key)]
+	}
+	return !IsEmpty(fieldValue)
+}
+
+// JSONName returns the key encoding/json uses for a field, or "" for fields
+// tagged `json:"-"`
+func JSONName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// Label returns the human readable name of a field from its `label` tag, or
+// "" when it has none
+func Label(field reflect.StructField) string {
+	return field.Tag.Get("label")
+}
+
+// JoinKey appends a JSON name to the dotted key of its parent. Fields without
+// a JSON name have no key.
+func JoinKey(prefix, name string) string {
+	if prefix == "" || name == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// JoinPath appends a field name to the error path of its parent, like
+// `billing.zip`
+func JoinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// JoinPointer appends a JSON name to the RFC 6901 JSON Pointer of its parent,
+// escaping `~` and `/` in the name
+func JoinPointer(pointer, name string) string {
+	if name == "" {
+		return ""
+	}
+	return pointer + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
@@ -0,0 +1,278 @@
+package enforcements
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// timeLayouts are tried in order for absolute times in tags. Colons must be
+// written as semicolons because of the way tags are split, and spaces can't be
+// used at all since they separate the options of a tag.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02",
+}
+
+// Clock reports the current time. Swapping it lets tests pin the time used by
+// time-relative defaults and enforcements.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to the Clock interface
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+var (
+	timeMu       sync.RWMutex
+	timeClock    Clock = ClockFunc(time.Now)
+	timeLocation       = time.Local
+)
+
+// SetClock sets the package wide clock used when a call does not provide one
+func SetClock(c Clock) {
+	timeMu.Lock()
+	defer timeMu.Unlock()
+	timeClock = c
+}
+
+// SetTimeLocation sets the package wide location that `timeNow`, anchors such as
+// `startOfDay` and absolute dates without an offset are evaluated in
+func SetTimeLocation(loc *time.Location) {
+	timeMu.Lock()
+	defer timeMu.Unlock()
+	timeLocation = loc
+}
+
+// timeAnchors resolve the start of a relative time expression
+var timeAnchors = map[string]func(now time.Time) time.Time{
+	"timeNow": func(now time.Time) time.Time { return now },
+	"startOfDay": func(now time.Time) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	},
+	"endOfDay": func(now time.Time) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, -1, now.Location())
+	},
+	"startOfWeek": func(now time.Time) time.Time {
+		sinceMonday := (int(now.Weekday()) + 6) % 7
+		return time.Date(now.Year(), now.Month(), now.Day()-sinceMonday, 0, 0, 0, 0, now.Location())
+	},
+	"endOfWeek": func(now time.Time) time.Time {
+		untilSunday := (7 - int(now.Weekday())) % 7
+		return time.Date(now.Year(), now.Month(), now.Day()+untilSunday+1, 0, 0, 0, -1, now.Location())
+	},
+	"startOfMonth": func(now time.Time) time.Time {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	},
+	"endOfMonth": func(now time.Time) time.Time {
+		return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, -1, now.Location())
+	},
+	"startOfYear": func(now time.Time) time.Time {
+		return time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+	},
+	"endOfYear": func(now time.Time) time.Time {
+		return time.Date(now.Year()+1, time.January, 1, 0, 0, 0, -1, now.Location())
+	},
+}
+
+// timeShift is a signed calendar offset. Years, months and days follow the
+// calendar while clock is an exact duration.
+type timeShift struct {
+	years, months, days int
+	clock               time.Duration
+}
+
+func (s timeShift) negate() timeShift {
+	return timeShift{years: -s.years, months: -s.months, days: -s.days, clock: -s.clock}
+}
+
+// apply adds the shift to t. Month and year shifts clamp to the end of the target
+// month, so Jan 31 + 1 month is the last day of February rather than early March.
+func (s timeShift) apply(t time.Time) time.Time {
+	if s.years != 0 || s.months != 0 {
+		year, month, day := t.Date()
+		firstOfTarget := time.Date(year+s.years, month+time.Month(s.months), 1, 0, 0, 0, 0, t.Location())
+		if lastDay := firstOfTarget.AddDate(0, 1, -1).Day(); day > lastDay {
+			day = lastDay
+		}
+		hour, min, sec := t.Clock()
+		t = time.Date(firstOfTarget.Year(), firstOfTarget.Month(), day, hour, min, sec, t.Nanosecond(), t.Location())
+	}
+	return t.AddDate(0, 0, s.days).Add(s.clock)
+}
+
+var isoDurationPattern = regexp.MustCompile(
+	`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`,
+)
+
+// parseTimeShift reads a single shift, either `N_unit` such as `5_days` or an
+// ISO 8601 duration such as `P1M2D` or `PT30M`
+func parseTimeShift(s string) (timeShift, error) {
+	if strings.HasPrefix(s, "P") {
+		m := isoDurationPattern.FindStringSubmatch(s)
+		if m == nil || s == "P" || strings.HasSuffix(s, "T") {
+			return timeShift{}, fmt.Errorf("invalid ISO 8601 duration '%s'", s)
+		}
+		n := make([]int, len(m))
+		for i := 1; i < len(m); i++ {
+			if m[i] != "" {
+				n[i], _ = strconv.Atoi(m[i])
+			}
+		}
+		return timeShift{
+			years:  n[1],
+			months: n[2],
+			days:   n[3]*7 + n[4],
+			clock:  time.Duration(n[5])*time.Hour + time.Duration(n[6])*time.Minute + time.Duration(n[7])*time.Second,
+		}, nil
+	}
+
+	values := strings.Split(s, "_")
+	if len(values) != 2 {
+		return timeShift{}, errors.New("error parsing time shift")
+	}
+	n, err := strconv.Atoi(values[0])
+	if err != nil {
+		return timeShift{}, errors.New("error parsing time shift")
+	}
+	switch strings.TrimSuffix(values[1], "s") {
+	case "year":
+		return timeShift{years: n}, nil
+	case "month":
+		return timeShift{months: n}, nil
+	case "week":
+		return timeShift{days: 7 * n}, nil
+	case "day":
+		return timeShift{days: n}, nil
+	case "hour":
+		return timeShift{clock: time.Duration(n) * time.Hour}, nil
+	case "minute":
+		return timeShift{clock: time.Duration(n) * time.Minute}, nil
+	case "second":
+		return timeShift{clock: time.Duration(n) * time.Second}, nil
+	}
+	return timeShift{}, errors.New("invalid time unit")
+}
+
+// parseRelativeTime evaluates an anchor followed by any number of signed shifts,
+// e.g. `timeNow+1_month-2_days` or `startOfDay+PT9H`
+func parseRelativeTime(value string, now time.Time) (time.Time, bool, error) {
+	end := strings.IndexAny(value, "+-")
+	if end < 0 {
+		end = len(value)
+	}
+	anchor, ok := timeAnchors[value[:end]]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	t := anchor(now)
+	rest := value[end:]
+	for rest != "" {
+		sign := rest[0]
+		next := strings.IndexAny(rest[1:], "+-")
+		if next < 0 {
+			next = len(rest) - 1
+		}
+		shift, err := parseTimeShift(rest[1 : next+1])
+		if err != nil {
+			return time.Time{}, true, err
+		}
+		if sign == '-' {
+			shift = shift.negate()
+		}
+		t = shift.apply(t)
+		rest = rest[next+1:]
+	}
+	return t, true, nil
+}
+
+// parseTimeValue reads an absolute time or a relative one such as `timeNow`,
+// `timeNow+5_days`, `timeNow-18_years` or `endOfMonth+1_day`
+func parseTimeValue(value string, now time.Time) (time.Time, error) {
+	value = strings.ReplaceAll(value, ";", ":")
+	if t, ok, err := parseRelativeTime(value, now); ok {
+		return t, err
+	}
+
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time '%s'", value)
+}
+
+func toTime(value interface{}) (time.Time, bool) {
+	t, ok := value.(time.Time)
+	return t, ok
+}
+
+func HandleBefore(value interface{}, fieldName, opt string, now time.Time) string {
+	t, ok := toTime(value)
+	if !ok {
+		return fmt.Sprintf("Unsupported type%s", ForField(fieldName))
+	}
+	bound, err := parseTimeValue(strings.TrimPrefix(opt, "before:"), now)
+	if err != nil {
+		return fmt.Sprintf("Invalid before value%s", ForField(fieldName))
+	}
+
+	if !t.Before(bound) {
+		return fmt.Sprintf("%s must be before %s", Subject(fieldName), bound.Format(time.RFC3339))
+	}
+	return ""
+}
+
+func HandleAfter(value interface{}, fieldName, opt string, now time.Time) string {
+	t, ok := toTime(value)
+	if !ok {
+		return fmt.Sprintf("Unsupported type%s", ForField(fieldName))
+	}
+	bound, err := parseTimeValue(strings.TrimPrefix(opt, "after:"), now)
+	if err != nil {
+		return fmt.Sprintf("Invalid after value%s", ForField(fieldName))
+	}
+
+	if !t.After(bound) {
+		return fmt.Sprintf("%s must be after %s", Subject(fieldName), bound.Format(time.RFC3339))
+	}
+	return ""
+}
+
+// HandleBetweenTime checks `betweenTime:<from>,<to>` with both ends inclusive
+func HandleBetweenTime(value interface{}, fieldName, opt string, now time.Time) string {
+	t, ok := toTime(value)
+	if !ok {
+		return fmt.Sprintf("Unsupported type%s", ForField(fieldName))
+	}
+	rangeVals := strings.Split(strings.TrimPrefix(opt, "betweenTime:"), ",")
+	if len(rangeVals) != 2 {
+		return fmt.Sprintf("Invalid time range%s", ForField(fieldName))
+	}
+
+	from, err := parseTimeValue(rangeVals[0], now)
+	if err != nil {
+		return fmt.Sprintf("Invalid time range%s", ForField(fieldName))
+	}
+	to, err := parseTimeValue(rangeVals[1], now)
+	if err != nil {
+		return fmt.Sprintf("Invalid time range%s", ForField(fieldName))
+	}
+
+	if t.Before(from) || t.After(to) {
+		return fmt.Sprintf(
+			"%s must be between %s and %s",
+			Subject(fieldName), from.Format(time.RFC3339), to.Format(time.RFC3339),
+		)
+	}
+	return ""
+}
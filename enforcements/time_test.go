@@ -0,0 +1,109 @@
+package enforcements
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestParseTimeValue(t *testing.T) {
+	jan31 := date(2023, time.January, 31, 10, 0)
+	tests := []struct {
+		value string
+		now   time.Time
+		want  time.Time
+	}{
+		{"timeNow", jan31, jan31},
+		{"timeNow+1_month", jan31, date(2023, time.February, 28, 10, 0)},
+		{"timeNow+1_month", date(2024, time.January, 31, 10, 0), date(2024, time.February, 29, 10, 0)},
+		{"timeNow+1_months", date(2023, time.March, 31, 10, 0), date(2023, time.April, 30, 10, 0)},
+		{"timeNow-1_month", date(2023, time.March, 31, 10, 0), date(2023, time.February, 28, 10, 0)},
+		{"timeNow+1_year", date(2024, time.February, 29, 10, 0), date(2025, time.February, 28, 10, 0)},
+		{"timeNow+1_month-2_days", jan31, date(2023, time.February, 26, 10, 0)},
+		{"timeNow-18_years", jan31, date(2005, time.January, 31, 10, 0)},
+		{"timeNow+2_weeks+3_hours", jan31, date(2023, time.February, 14, 13, 0)},
+		{"timeNow+P1M2D", jan31, date(2023, time.March, 2, 10, 0)},
+		{"timeNow+PT30M", jan31, date(2023, time.January, 31, 10, 30)},
+		{"timeNow-P1W", jan31, date(2023, time.January, 24, 10, 0)},
+		{"startOfDay+PT9H", jan31, date(2023, time.January, 31, 9, 0)},
+		{"startOfMonth", jan31, date(2023, time.January, 1, 0, 0)},
+		{"endOfMonth+1_day", jan31, date(2023, time.February, 2, 0, 0).Add(-time.Nanosecond)},
+		// January 31st 2023 is a Tuesday
+		{"startOfWeek", jan31, date(2023, time.January, 30, 0, 0)},
+		{"startOfYear", jan31, date(2023, time.January, 1, 0, 0)},
+		{"2023-06-01", jan31, date(2023, time.June, 1, 0, 0)},
+		{"2023-06-01T12;30;00Z", jan31, date(2023, time.June, 1, 12, 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseTimeValue(tt.value, tt.now)
+			if err != nil {
+				t.Fatalf("parseTimeValue(%q) failed: %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseTimeValue(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimeValueInvalid(t *testing.T) {
+	now := date(2023, time.January, 31, 10, 0)
+	for _, value := range []string{
+		"tomorrow",
+		"timeNow+",
+		"timeNow+5",
+		"timeNow+5_fortnights",
+		"timeNow+x_days",
+		"timeNow+P",
+		"timeNow+PT",
+		"timeNow+P1H",
+		"2023-13-01",
+	} {
+		if _, err := parseTimeValue(value, now); err == nil {
+			t.Errorf("parseTimeValue(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestHandleBetweenTime(t *testing.T) {
+	now := date(2023, time.January, 31, 10, 0)
+	opt := "betweenTime:startOfDay,endOfDay"
+	tests := []struct {
+		value   interface{}
+		wantErr bool
+	}{
+		{date(2023, time.January, 31, 0, 0), false},
+		{date(2023, time.January, 31, 23, 59), false},
+		{date(2023, time.February, 1, 0, 0), true},
+		{"2023-01-31", true},
+	}
+	for _, tt := range tests {
+		if got := HandleBetweenTime(tt.value, "At", opt, now); (got != "") != tt.wantErr {
+			t.Errorf("HandleBetweenTime(%v) = %q, want error %v", tt.value, got, tt.wantErr)
+		}
+	}
+}
+
+func TestParseTimeValueAbsolute(t *testing.T) {
+	now := date(2023, time.January, 31, 10, 0)
+	kathmandu := time.FixedZone("", 5*3600+45*60)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-01-01", date(2024, time.January, 1, 0, 0)},
+		{"2024-01-01T00;00;00Z", date(2024, time.January, 1, 0, 0)},
+		{"2024-01-01T00;00;00+05;45", time.Date(2024, time.January, 1, 0, 0, 0, 0, kathmandu)},
+		{"2023-06-15T00:00:00+05:45", time.Date(2023, time.June, 15, 0, 0, 0, 0, kathmandu)},
+	}
+	for _, tt := range tests {
+		got, err := parseTimeValue(tt.value, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseTimeValue(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package enforcements
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// HandleURL checks for an absolute URL with a scheme and a host, such as
+// `https://example.com/path`, or an opaque URL such as `mailto:a@b.co`
+func HandleURL(fieldValue, fieldName, opt string) string {
+	u, err := url.Parse(fieldValue)
+	if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+		return fmt.Sprintf("%s must be a valid URL", Subject(fieldName))
+	}
+	return ""
+}
@@ -1,11 +1,35 @@
 package enforcements
 
 import (
+	"fmt"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
+// SiblingLookup returns another field of the struct being validated. It is nil
+// when a single variable is validated.
+type SiblingLookup func(name string) (reflect.Value, bool)
+
+// Subject starts a message about the checked value, "Field 'Name'" for a
+// struct field or "Value" for a single variable, which has no name
+func Subject(fieldName string) string {
+	if fieldName == "" {
+		return "Value"
+	}
+	return fmt.Sprintf("Field '%s'", fieldName)
+}
+
+// ForField names the field a tag mistake belongs to, like " for field 'Name'",
+// and is empty for a single variable
+func ForField(fieldName string) string {
+	if fieldName == "" {
+		return ""
+	}
+	return fmt.Sprintf(" for field '%s'", fieldName)
+}
+
 func ExtractNumber(str string) string {
 	re := regexp.MustCompile(`\d+`)
 	match := re.FindString(str)
@@ -25,6 +49,15 @@ func IsIntType(k reflect.Kind) bool {
 	}
 }
 
+func IsUintType(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
 func IsFloatType(k reflect.Kind) bool {
 	switch k {
 	case reflect.Float32, reflect.Float64:
@@ -34,10 +67,35 @@ func IsFloatType(k reflect.Kind) bool {
 	}
 }
 
-func IsEmpty(v reflect.Value) bool{
+// IsEmpty reports whether a value counts as not provided. Pointers and
+// interfaces are empty only when nil, so a pointer to 0 or "" is provided.
+func IsEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
 	return (IsString(v.Kind()) && v.String() == "") || (IsIntType(v.Kind()) && v.Int() == 0) || (IsFloatType(v.Kind()) && v.Float() == 0.0)
 }
 
+// toFloat converts numeric kinds and numeric strings to a float64
+func toFloat(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch {
+	case IsIntType(v.Kind()):
+		return float64(v.Int()), true
+	case IsUintType(v.Kind()):
+		return float64(v.Uint()), true
+	case IsFloatType(v.Kind()):
+		return v.Float(), true
+	case IsString(v.Kind()):
+		f, err := strconv.ParseFloat(strings.TrimSpace(v.String()), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
 func containsUppercase(s string) bool {
 	for _, c := range s {
 		if 'A' <= c && c <= 'Z' {
@@ -8,25 +8,23 @@ import (
 
 func HandleWordCount(fieldValue, fieldName, opt string) string {
 	rangeVals := strings.Split(strings.TrimPrefix(opt, "wordCount:"), ",")
-	fmt.Printf("\n%#v\n", rangeVals)
-
 	if len(rangeVals) != 2 {
-		return fmt.Sprintf("Invalid word count range for field '%s'", fieldName)
+		return fmt.Sprintf("Invalid word count range%s", ForField(fieldName))
 	}
 
 	min, err := strconv.Atoi(rangeVals[0])
 	if err != nil {
-		return fmt.Sprintf("Invalid word count range for field '%s'", fieldName)
+		return fmt.Sprintf("Invalid word count range%s", ForField(fieldName))
 	}
 
 	max, err := strconv.Atoi(rangeVals[1])
 	if err != nil {
-		return fmt.Sprintf("Invalid word count range for field '%s'", fieldName)
+		return fmt.Sprintf("Invalid word count range%s", ForField(fieldName))
 	}
 
 	words := countWords(fieldValue)
 	if words < min || words > max {
-		return fmt.Sprintf("Field '%s' must be between %s and %s words", fieldName, rangeVals[0], rangeVals[1])
+		return fmt.Sprintf("%s must be between %s and %s words", Subject(fieldName), rangeVals[0], rangeVals[1])
 	}
 
 	return ""
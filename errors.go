@@ -0,0 +1,51 @@
+package enforcer
+
+import (
+	"strings"
+
+	"github.com/rrojan/enforcer/enforcements"
+)
+
+// FieldError is a single failed enforcement on a field
+type FieldError = enforcements.FieldError
+
+// ConfigError is returned by a Rule's Check for a mistake in the tag that only
+// shows up with a value. It is reported as a *SchemaError.
+type ConfigError = enforcements.ConfigError
+
+// ValidationErrors holds every FieldError found by Check or CheckVar
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	return strings.Join(e.Messages(), "; ")
+}
+
+// Messages returns the error message of every FieldError in order
+func (e ValidationErrors) Messages() []string {
+	var messages []string
+	for _, err := range e {
+		messages = append(messages, err.Message)
+	}
+	return messages
+}
+
+// add records a failed enforcement, ignoring the empty message handlers return on success
+func (e *ValidationErrors) add(field, opt, message string) {
+	if message == "" {
+		return
+	}
+	*e = append(*e, &FieldError{Field: field, Rule: ruleName(opt), Message: message})
+}
+
+// addError records an enforcement that already reports a FieldError
+func (e *ValidationErrors) addError(err *FieldError) {
+	if err != nil {
+		*e = append(*e, err)
+	}
+}
+
+// ruleName strips the parameters from an enforcement such as `between:2,10`
+func ruleName(opt string) string {
+	name, _, _ := strings.Cut(opt, ":")
+	return name
+}
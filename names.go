@@ -0,0 +1,45 @@
+package enforcer
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/rrojan/enforcer/enforcements"
+)
+
+// Built-in NameMappers for WithNameMapper, naming fields by the key clients
+// send them under
+var (
+	JSONNames  = TagNames("json")
+	FormNames  = TagNames("form")
+	QueryNames = TagNames("query")
+)
+
+// TagNames returns a NameMapper that names fields by a struct tag such as
+// `json:"user_type,omitempty"`. Options after the comma are ignored, and
+// fields tagged `-` or without the tag keep their Go field name.
+func TagNames(key string) NameMapper {
+	return func(field reflect.StructField) string {
+		tag := field.Tag.Get(key)
+		if tag == "-" {
+			return ""
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		return name
+	}
+}
+
+// fieldLocOf locates a field of the struct at parent. Messages name it by its
+// `label` tag when it has one.
+func fieldLocOf(settings enforcements.Settings, field reflect.StructField, parent fieldLoc) fieldLoc {
+	loc := fieldLoc{
+		path:    enforcements.JoinPath(parent.path, settings.NameOf(field)),
+		key:     enforcements.JoinKey(parent.key, enforcements.JSONName(field)),
+		pointer: enforcements.JoinPointer(parent.pointer, enforcements.JSONName(field)),
+	}
+	loc.name = enforcements.Label(field)
+	if loc.name == "" {
+		loc.name = loc.path
+	}
+	return loc
+}
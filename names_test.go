@@ -0,0 +1,79 @@
+package enforcer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTagNames(t *testing.T) {
+	type form struct {
+		UserType string `json:"type,omitempty" form:"user_type" query:"t"`
+		Secret   string `json:"-" form:"-"`
+		Plain    string
+		Empty    string `json:",omitempty"`
+	}
+	tests := []struct {
+		mapper NameMapper
+		field  string
+		want   string
+	}{
+		{JSONNames, "UserType", "type"},
+		{FormNames, "UserType", "user_type"},
+		{QueryNames, "UserType", "t"},
+		{JSONNames, "Secret", ""},
+		{FormNames, "Secret", ""},
+		{JSONNames, "Plain", ""},
+		{JSONNames, "Empty", ""},
+	}
+	for _, tt := range tests {
+		field, _ := reflect.TypeOf(form{}).FieldByName(tt.field)
+		if got := tt.mapper(field); got != tt.want {
+			t.Errorf("mapper(%s) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestJSONNamesInErrors(t *testing.T) {
+	type item struct {
+		SKU string `json:"sku" enforce:"required"`
+	}
+	type billing struct {
+		ZipCode string          `json:"zip_code" label:"ZIP code" enforce:"min:5"`
+		Items   []item          `json:"items"`
+		ByName  map[string]item `json:"by_name"`
+	}
+	type order struct {
+		UserType string  `json:"type" enforce:"enum:admin,user"`
+		Internal string  `json:"-" enforce:"required"`
+		Billing  billing `json:"billing"`
+	}
+	req := order{
+		UserType: "root",
+		Billing: billing{
+			ZipCode: "123",
+			Items:   []item{{SKU: "a"}, {}},
+			ByName:  map[string]item{"gift": {}},
+		},
+	}
+
+	want := []struct{ field, path, pointer string }{
+		{"type", "type", "/type"},
+		{"Internal", "", ""},
+		{"billing.zip_code", "billing.zip_code", "/billing/zip_code"},
+		{"billing.items[1].sku", "billing.items.1.sku", "/billing/items/1/sku"},
+		{"billing.by_name[gift].sku", "billing.by_name.gift.sku", "/billing/by_name/gift/sku"},
+	}
+	var errs ValidationErrors
+	if err := New(WithNameMapper(JSONNames)).Check(&req); !errors.As(err, &errs) || len(errs) != len(want) {
+		t.Fatalf("Check() = %v, want %d failures", err, len(want))
+	}
+	for i, w := range want {
+		if errs[i].Field != w.field || errs[i].Path != w.path || errs[i].Pointer != w.pointer {
+			t.Errorf("failure %d = %q, %q, %q, want %q, %q, %q", i, errs[i].Field, errs[i].Path, errs[i].Pointer, w.field, w.path, w.pointer)
+		}
+	}
+	if got := errs[2].Message; got != "Field 'ZIP code' must be at least 5 characters long" {
+		t.Errorf("labelled message = %q", got)
+	}
+}
@@ -0,0 +1,34 @@
+package enforcer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOptional(t *testing.T) {
+	type order struct {
+		Qty  *int `json:"qty" enforce:"optional min:1"`
+		Size int  `json:"size" enforce:"optional min:1"`
+	}
+	zero, two := 0, 2
+	tests := []struct {
+		name      string
+		req       order
+		opts      []Option
+		wantRules []string
+	}{
+		{"nothing sent", order{}, nil, nil},
+		{"valid values", order{Qty: &two, Size: 2}, nil, nil},
+		{"pointer to zero", order{Qty: &zero}, nil, []string{"min"}},
+		{"zero sent", order{}, []Option{WithPresentKeys([]string{"size"})}, []string{"min"}},
+		{"zero not sent", order{}, []Option{WithPresentKeys([]string{"qty"})}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			if got := failedRules(t, &req, tt.opts...); !reflect.DeepEqual(got, tt.wantRules) {
+				t.Errorf("failed rules = %v, want %v", got, tt.wantRules)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package enforcer
+
+import (
+	"context"
+	"time"
+
+	"github.com/rrojan/enforcer/enforcements"
+)
+
+// Clock reports the current time for time-relative defaults and enforcements
+type Clock = enforcements.Clock
+
+// ClockFunc adapts a function such as time.Now to the Clock interface
+type ClockFunc = enforcements.ClockFunc
+
+// Option configures a single Validate, ValidateVar or CustomValidator call
+type Option func(*enforcements.Settings)
+
+// WithClock makes the call read the current time from c
+func WithClock(c Clock) Option {
+	return func(s *enforcements.Settings) {
+		s.Clock = c
+	}
+}
+
+// WithLocation makes the call evaluate `timeNow`, anchors and dates without an
+// offset in loc
+func WithLocation(loc *time.Location) Option {
+	return func(s *enforcements.Settings) {
+		s.Location = loc
+	}
+}
+
+// WithContext passes ctx to the default providers resolved during the call and
+// to lookups such as the MX records of `email:mx`
+func WithContext(ctx context.Context) Option {
+	return func(s *enforcements.Settings) {
+		s.Context = ctx
+	}
+}
+
+// Prohibit modes for `prohibit:<mode>` and WithProhibitMode
+const (
+	ProhibitStrip  = enforcements.ProhibitStrip
+	ProhibitReport = enforcements.ProhibitReport
+	ProhibitBoth   = enforcements.ProhibitBoth
+)
+
+// StripAudit receives the dotted JSON path of every prohibited field that was stripped
+type StripAudit = enforcements.StripAudit
+
+// WithProhibitMode sets the mode of a bare `prohibit` for the call. Fields
+// are stripped silently when it is not set.
+func WithProhibitMode(mode string) Option {
+	return func(s *enforcements.Settings) {
+		s.ProhibitMode = mode
+	}
+}
+
+// WithStripAudit makes the call report every prohibited field it strips to audit
+func WithStripAudit(audit StripAudit) Option {
+	return func(s *enforcements.Settings) {
+		s.OnStrip = audit
+	}
+}
+
+// WithOmitEmpty treats every field that is not `required` as `optional`, so
+// empty fields skip their rules
+func WithOmitEmpty() Option {
+	return func(s *enforcements.Settings) {
+		s.OmitEmpty = true
+	}
+}
+
+// SetClock sets the clock used by calls that do not pass WithClock
+func SetClock(c Clock) {
+	enforcements.SetClock(c)
+}
+
+// SetTimeLocation sets the location used by calls that do not pass WithLocation.
+// It defaults to time.Local.
+func SetTimeLocation(loc *time.Location) {
+	enforcements.SetTimeLocation(loc)
+}
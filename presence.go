@@ -0,0 +1,70 @@
+package enforcer
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/rrojan/enforcer/enforcements"
+)
+
+// WithPresentKeys tells the call which JSON keys the client actually sent, so
+// `required` and `default:` can tell an explicit 0, "" or false apart from a
+// missing field. Nested keys are dotted paths such as `address.city`. Keys are
+// matched case-insensitively like encoding/json does.
+func WithPresentKeys(keys []string) Option {
+	return func(s *enforcements.Settings) {
+		s.PresentKeys = make(map[string]bool, len(keys))
+		for _, key := range keys {
+			s.PresentKeys[strings.ToLower(key)] = true
+		}
+	}
+}
+
+// PresentKeys lists the keys of a JSON object body for WithPresentKeys. Keys of
+// nested objects are added as dotted paths, with the index for objects inside
+// arrays like `items.2.qty`. Keys set to null count as not sent.
+func PresentKeys(body []byte) ([]string, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, err
+	}
+	var keys []string
+	collectKeys(obj, "", &keys)
+	return keys, nil
+}
+
+func collectKeys(obj map[string]json.RawMessage, prefix string, keys *[]string) {
+	for name, raw := range obj {
+		if string(raw) == "null" {
+			continue
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		*keys = append(*keys, key)
+		collectNested(raw, key, keys)
+	}
+}
+
+// collectNested adds the keys of an object, or of the objects in an array
+func collectNested(raw json.RawMessage, key string, keys *[]string) {
+	var nested map[string]json.RawMessage
+	if json.Unmarshal(raw, &nested) == nil && nested != nil {
+		collectKeys(nested, key, keys)
+		return
+	}
+	var elems []json.RawMessage
+	if json.Unmarshal(raw, &elems) != nil {
+		return
+	}
+	for i, elem := range elems {
+		if string(elem) == "null" {
+			continue
+		}
+		elemKey := key + "." + strconv.Itoa(i)
+		*keys = append(*keys, elemKey)
+		collectNested(elem, elemKey, keys)
+	}
+}
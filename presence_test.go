@@ -0,0 +1,76 @@
+package enforcer
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestRequiredPresence(t *testing.T) {
+	type item struct {
+		Qty int `json:"qty" enforce:"required"`
+	}
+	type cart struct {
+		Count int    `json:"count" enforce:"required"`
+		Items []item `json:"items"`
+	}
+
+	body := []byte(`{"count": 0, "items": [{"qty": 0}, {}, null]}`)
+	keys, err := PresentKeys(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(keys)
+	wantKeys := []string{"count", "items", "items.0", "items.0.qty", "items.1"}
+	if !reflect.DeepEqual(keys, wantKeys) {
+		t.Errorf("PresentKeys = %v, want %v", keys, wantKeys)
+	}
+
+	req := cart{Items: []item{{}, {}}}
+	err = Check(&req, WithPresentKeys(keys))
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("Check = %v, want one missing field", err)
+	}
+	if e := errs[0]; e.Field != "Items[1].Qty" || e.Path != "items.1.qty" || e.Pointer != "/items/1/qty" || e.Rule != "required" {
+		t.Errorf("Check reported %+v, want required at items.1.qty", e)
+	}
+
+	// Without the keys a zero value counts as missing
+	if got := failedRules(t, &cart{}); !reflect.DeepEqual(got, []string{"required"}) {
+		t.Errorf("failed rules without present keys = %v, want [required]", got)
+	}
+}
+
+func TestDefaultPresence(t *testing.T) {
+	type page struct {
+		Page int `json:"page" enforce:"default:1"`
+		Size int `json:"size" enforce:"default:20"`
+	}
+	req := page{}
+	if err := Check(&req, WithPresentKeys([]string{"page"})); err != nil {
+		t.Fatal(err)
+	}
+	if req.Page != 0 || req.Size != 20 {
+		t.Errorf("got %+v, want the sent page kept at 0 and size defaulted to 20", req)
+	}
+}
+
+// failedRules validates req and returns the rules that failed
+func failedRules(t *testing.T, req interface{}, opts ...Option) []string {
+	t.Helper()
+	err := Check(req, opts...)
+	if err == nil {
+		return nil
+	}
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Check returned %v, want ValidationErrors", err)
+	}
+	var rules []string
+	for _, e := range errs {
+		rules = append(rules, e.Rule)
+	}
+	return rules
+}
@@ -0,0 +1,13 @@
+package enforcer
+
+import "github.com/rrojan/enforcer/enforcements"
+
+// BoundingBox is a rectangular region in degrees used by the `within` enforcement
+type BoundingBox = enforcements.BoundingBox
+
+// RegisterRegion makes a named bounding box available as `within:<name>`.
+// Regions are usually registered once at startup; structs compiled before
+// are compiled again.
+func RegisterRegion(name string, box BoundingBox) {
+	enforcements.RegisterRegion(name, box)
+}
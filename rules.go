@@ -0,0 +1,407 @@
+package enforcer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/rrojan/enforcer/enforcements"
+)
+
+// FieldContext is what a rule gets to check a single value
+type FieldContext struct {
+	// Value is the value being checked, with pointers dereferenced
+	Value reflect.Value
+	// Field is the name used in messages, "" for ValidateVar
+	Field string
+	// Opt is the whole option from the tag, such as `between:2,10`
+	Opt string
+	// Param is the part of Opt after the first `:`
+	Param string
+	// Parsed is what Rule.Parse returned for Param, nil without a parser
+	Parsed interface{}
+	// Provided reports whether the client sent the value
+	Provided bool
+	// Now is the current time of the call's clock
+	Now time.Time
+	// Sibling looks up other fields of the struct, nil for ValidateVar
+	Sibling  enforcements.SiblingLookup
+	Settings enforcements.Settings
+
+	custom CustomEnforcements
+}
+
+// String returns the value as text for rules that check strings
+func (fc FieldContext) String() string {
+	if fc.Value.Kind() == reflect.String {
+		return fc.Value.String()
+	}
+	return fmt.Sprint(fc.Value)
+}
+
+// Rule is a check that can be named in enforce tags. Built-in rules are
+// registered the same way, so a rule works in Validate, ValidateVar and
+// CustomValidator alike.
+type Rule struct {
+	Name string
+	// Kinds lists the kinds of values the rule supports. Other kinds are
+	// reported as a *SchemaError. Empty accepts every kind.
+	Kinds []reflect.Kind
+	// Parse reads the parameter once when a tag is compiled, so mistakes are
+	// reported as a *SchemaError. It is optional.
+	Parse func(param string) (interface{}, error)
+	// Check returns nil when the value passes. A *FieldError or
+	// ValidationErrors is reported as is, any other error by its message.
+	Check func(fc FieldContext) error
+}
+
+func (r Rule) supports(v reflect.Value) bool {
+	if len(r.Kinds) == 0 {
+		return true
+	}
+	for _, kind := range r.Kinds {
+		if v.Kind() == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// modifiers may appear in tags but are handled outside of the rule checks
+var modifiers = map[string]bool{
+	"optional": true, "omitempty": true, "default": true, "prohibit": true, "toE164": true, "dive": true,
+}
+
+// registry holds rules by name
+type registry struct {
+	mu    sync.RWMutex
+	rules map[string]Rule
+}
+
+func newRegistry(rules []Rule) *registry {
+	r := &registry{rules: make(map[string]Rule, len(rules))}
+	for _, rule := range rules {
+		if err := r.register(rule); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// RegisterRule makes a rule available to the enforce tags of the package level
+// functions. Register rules at startup; structs compiled before are compiled again.
+func RegisterRule(rule Rule) error {
+	return defaultValidator.RegisterRule(rule)
+}
+
+func (r *registry) register(rule Rule) error {
+	if rule.Name == "" || strings.ContainsAny(rule.Name, ": ") || rule.Check == nil {
+		return errors.New("enforcer: a rule needs a name without spaces or colons and a Check function")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rules[rule.Name]; ok || modifiers[rule.Name] {
+		return fmt.Errorf("enforcer: rule '%s' is already registered", rule.Name)
+	}
+	r.rules[rule.Name] = rule
+	return nil
+}
+
+// boundRule is a rule resolved from one option of a tag
+type boundRule struct {
+	rule   Rule
+	opt    string
+	param  string
+	parsed interface{}
+}
+
+// fieldRules are the rules bound from the options of a tag. Options after
+// `dive` apply to each element of a slice, array or map.
+type fieldRules struct {
+	opts  []string
+	rules []boundRule
+	dive  *fieldRules
+}
+
+// bind resolves every option of a tag by its exact name, the part before the
+// first `:`. Modifiers are left out of the bound rules. Mistakes are returned
+// as a *SchemaError for the caller to name the struct and field.
+func (r *registry) bind(opts []string) (*fieldRules, *SchemaError) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.bindLocked(opts)
+}
+
+func (r *registry) bindLocked(opts []string) (*fieldRules, *SchemaError) {
+	fr := &fieldRules{opts: opts}
+	for i, opt := range opts {
+		name, param, _ := strings.Cut(opt, ":")
+		if name == "dive" {
+			fr.opts = opts[:i]
+			dive, err := r.bindLocked(opts[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			fr.dive = dive
+			return fr, nil
+		}
+		if name == "" || modifiers[name] {
+			continue
+		}
+		rule, ok := r.rules[name]
+		if !ok {
+			message := fmt.Sprintf("unknown rule '%s'", name)
+			if suggestion := r.closestRule(name); suggestion != "" {
+				message += fmt.Sprintf(", did you mean '%s'?", suggestion)
+			}
+			return nil, &SchemaError{Rule: name, Message: message}
+		}
+		b := boundRule{rule: rule, opt: opt, param: param}
+		if rule.Parse != nil {
+			parsed, err := rule.Parse(param)
+			if err != nil {
+				return nil, &SchemaError{Rule: name, Message: fmt.Sprintf("invalid parameter for rule '%s': %s", name, err)}
+			}
+			b.parsed = parsed
+		}
+		fr.rules = append(fr.rules, b)
+	}
+	return fr, nil
+}
+
+// closestRule returns the known name with the smallest edit distance to name,
+// or "" when none is close enough to be a likely typo
+func (r *registry) closestRule(name string) string {
+	known := make([]string, 0, len(r.rules)+len(modifiers))
+	for rule := range r.rules {
+		known = append(known, rule)
+	}
+	for modifier := range modifiers {
+		known = append(known, modifier)
+	}
+	// Sorted so ties always suggest the same rule
+	sort.Strings(known)
+
+	// Allow about a third of the name to differ, so `requried` finds
+	// `required` while `regex` does not turn into `hex`
+	maxDistance := len(name) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+	best, bestDistance := "", maxDistance+1
+	for _, rule := range known {
+		d := editDistance(name, rule)
+		// Changing every character of either name is a replacement, not a typo
+		if d >= len(name) || d >= len(rule) {
+			continue
+		}
+		// Ties go to the rule sharing the longer prefix, `min` over `enum`
+		if d < bestDistance || (best != "" && d == bestDistance && commonPrefix(name, rule) > commonPrefix(name, best)) {
+			best, bestDistance = rule, d
+		}
+	}
+	return best
+}
+
+func commonPrefix(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// editDistance is the Levenshtein distance between a and b, where swapping two
+// adjacent characters also counts as a single edit
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// ruleCall holds what the rules of a single validation call share
+type ruleCall struct {
+	settings enforcements.Settings
+	now      time.Time
+	sibling  enforcements.SiblingLookup
+	custom   CustomEnforcements
+	// localize replaces messages from a message catalog, nil to keep them
+	localize func(err *FieldError, param string)
+	// mistake is the first mistake in a tag found while checking values
+	mistake *FieldError
+}
+
+// recordMistake keeps the first mistake in a tag found during the call
+func (c *ruleCall) recordMistake(loc fieldLoc, opt, message string) {
+	if c.mistake == nil {
+		c.mistake = &FieldError{Field: loc.path, Rule: ruleName(opt), Message: message}
+	}
+}
+
+// schemaError returns the mistake found in the tags of a struct, or nil
+func (c *ruleCall) schemaError(structName string) *SchemaError {
+	if c.mistake == nil {
+		return nil
+	}
+	return &SchemaError{Struct: structName, Field: c.mistake.Field, Rule: c.mistake.Rule, Message: c.mistake.Message}
+}
+
+// fieldLoc names a field in messages and locates it in errors
+type fieldLoc struct {
+	// name is the label of the field, or its path when it has none
+	name string
+	// path holds the resolved names from the top level struct, like `billing.items[2].qty`
+	path string
+	// key and pointer locate the field in the request body by its JSON keys
+	key     string
+	pointer string
+}
+
+// at returns the location of an element of a collection, such as `tags[2]`
+func (l fieldLoc) at(index string) fieldLoc {
+	return fieldLoc{
+		name:    l.name + "[" + index + "]",
+		path:    l.path + "[" + index + "]",
+		key:     enforcements.JoinKey(l.key, index),
+		pointer: enforcements.JoinPointer(l.pointer, index),
+	}
+}
+
+// validateField runs the rules of a single field or variable. fieldValue may be
+// a pointer, which is dereferenced when it is not nil.
+func (c *ruleCall) validateField(
+	errs *ValidationErrors, loc fieldLoc, fieldValue reflect.Value, provided bool, fr *fieldRules,
+) {
+	// A nil pointer was not sent, so only `required` applies to it. Other
+	// rules check the value a non-nil pointer points to.
+	fieldValue = readable(fieldValue)
+	absent := !fieldValue.IsValid() || (fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil())
+	if fieldValue.Kind() == reflect.Ptr && !absent {
+		fieldValue = fieldValue.Elem()
+	}
+	// Optional fields skip their rules when the client did not send them and no
+	// default filled them. A sent zero, or a pointer to one, is still checked.
+	skip := absent || (enforcements.IsOptional(fr.opts, c.settings) && !provided && enforcements.IsEmpty(fieldValue))
+
+	for _, b := range fr.rules {
+		if skip && b.rule.Name != "required" {
+			continue
+		}
+		fc := FieldContext{
+			Value:    fieldValue,
+			Field:    loc.name,
+			Opt:      b.opt,
+			Param:    b.param,
+			Parsed:   b.parsed,
+			Provided: provided,
+			Now:      c.now,
+			Sibling:  c.sibling,
+			Settings: c.settings,
+			custom:   c.custom,
+		}
+		before := len(*errs)
+		c.addRule(errs, loc, fc, b.rule)
+		for _, err := range (*errs)[before:] {
+			if c.localize != nil {
+				c.localize(err, b.param)
+			}
+			// Messages name the field by its label, errors locate it by its path
+			err.Field, err.Path, err.Pointer = loc.path, loc.key, loc.pointer
+		}
+	}
+
+	if fr.dive != nil && !absent {
+		c.validateElements(errs, loc, fieldValue, fr.dive)
+	}
+}
+
+// readable returns a value whose Interface can be called, so rules work on
+// unexported fields like they do on exported ones. Such fields are read through
+// their address, which every field has since validate only walks addressable
+// structs.
+func readable(v reflect.Value) reflect.Value {
+	if !v.IsValid() || v.CanInterface() || !v.CanAddr() {
+		return v
+	}
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+// validateElements runs the rules after `dive` on each element of a
+// collection, naming them like `tags[2]` or `limits[daily]`
+func (c *ruleCall) validateElements(errs *ValidationErrors, loc fieldLoc, collection reflect.Value, fr *fieldRules) {
+	switch collection.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < collection.Len(); i++ {
+			elem := collection.Index(i)
+			c.validateField(errs, loc.at(strconv.Itoa(i)), elem, !enforcements.IsEmpty(elem), fr)
+		}
+	case reflect.Map:
+		iter := collection.MapRange()
+		for iter.Next() {
+			elem := iter.Value()
+			c.validateField(errs, loc.at(fmt.Sprint(iter.Key())), elem, !enforcements.IsEmpty(elem), fr)
+		}
+	default:
+		c.recordMistake(loc, "dive", fmt.Sprintf("Unsupported type%s", enforcements.ForField(loc.name)))
+	}
+}
+
+// addRule runs a rule and records what it reports. Mistakes in the tag are
+// kept apart from the failures of the value.
+func (c *ruleCall) addRule(errs *ValidationErrors, loc fieldLoc, fc FieldContext, rule Rule) {
+	if fc.Value.IsValid() && !rule.supports(fc.Value) {
+		c.recordMistake(loc, fc.Opt, fmt.Sprintf("Unsupported type%s", enforcements.ForField(fc.Field)))
+		return
+	}
+
+	err := rule.Check(fc)
+	var configErr *ConfigError
+	var fieldErr *FieldError
+	var fieldErrs ValidationErrors
+	switch {
+	case err == nil:
+	case errors.As(err, &configErr):
+		c.recordMistake(loc, fc.Opt, configErr.Message)
+	case errors.As(err, &fieldErr):
+		errs.addError(fieldErr)
+	case errors.As(err, &fieldErrs):
+		*errs = append(*errs, fieldErrs...)
+	default:
+		errs.add(fc.Field, fc.Opt, err.Error())
+	}
+}
@@ -0,0 +1,190 @@
+package enforcer
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnexportedFields(t *testing.T) {
+	type point struct {
+		lat  float64   `enforce:"latitude"`
+		born time.Time `enforce:"minAge:18"`
+		name string    `enforce:"min:3"`
+	}
+	type trip struct {
+		Stops map[string]point
+	}
+	req := trip{Stops: map[string]point{"home": {lat: 100, born: time.Now(), name: "ab"}}}
+	want := []string{"latitude", "minAge", "min"}
+
+	if got := failedRules(t, &req); !reflect.DeepEqual(got, want) {
+		t.Errorf("failed rules by pointer = %v, want %v", got, want)
+	}
+	if got := failedRules(t, req); !reflect.DeepEqual(got, want) {
+		t.Errorf("failed rules by value = %v, want %v", got, want)
+	}
+}
+
+func TestClosestRule(t *testing.T) {
+	r := newRegistry(builtinRules())
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"requried", "required"},
+		{"matches", "match"},
+		{"betwen", "between"},
+		{"emial", "email"},
+		{"mni", "min"},
+		{"mn", "min"},
+		{"optinal", "optional"},
+		{"datetme", "datetime"},
+		{"xyz", ""},
+		{"regex", ""},
+		{"minimum", ""},
+		{"x", ""},
+		{"zzzzzzzz", ""},
+	}
+	for _, tt := range tests {
+		if got := r.closestRule(tt.name); got != tt.want {
+			t.Errorf("closestRule(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"min", "min", 0},
+		{"", "max", 3},
+		{"mni", "min", 1},
+		{"min", "max", 2},
+		{"regex", "hex", 3},
+		{"kitten", "sitting", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	check := func(fc FieldContext) error { return nil }
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr bool
+	}{
+		{"new rule", Rule{Name: "even", Check: check}, false},
+		{"no name", Rule{Check: check}, true},
+		{"space in name", Rule{Name: "is even", Check: check}, true},
+		{"colon in name", Rule{Name: "even:2", Check: check}, true},
+		{"no check", Rule{Name: "odd"}, true},
+		{"built-in name", Rule{Name: "min", Check: check}, true},
+		{"modifier name", Rule{Name: "optional", Check: check}, true},
+	}
+	r := newRegistry(builtinRules())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.register(tt.rule); (err != nil) != tt.wantErr {
+				t.Errorf("register() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistryBind(t *testing.T) {
+	r := newRegistry(builtinRules())
+	tests := []struct {
+		tag       string
+		wantRules []string
+		wantDive  []string
+		wantErr   string
+	}{
+		{tag: "required min:3", wantRules: []string{"required", "min"}},
+		{tag: "optional default:a enum:a,b", wantRules: []string{"enum"}},
+		{tag: "min:1 dive email", wantRules: []string{"min"}, wantDive: []string{"email"}},
+		{tag: "minimum:5", wantErr: "unknown rule 'minimum'"},
+		{tag: "requried", wantErr: "unknown rule 'requried', did you mean 'required'?"},
+		{tag: "min:abc", wantErr: "invalid parameter for rule 'min': 'abc' is not a number or a duration"},
+		{tag: "dive json:string", wantErr: "invalid parameter for rule 'json': unknown top level type 'string'"},
+	}
+	names := func(fr *fieldRules) []string {
+		var names []string
+		for _, b := range fr.rules {
+			names = append(names, b.rule.Name)
+		}
+		return names
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			fr, err := r.bind(strings.Split(tt.tag, " "))
+			if tt.wantErr != "" {
+				if err == nil || err.Message != tt.wantErr {
+					t.Fatalf("bind() = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("bind() = %v", err)
+			}
+			if got := names(fr); !reflect.DeepEqual(got, tt.wantRules) {
+				t.Errorf("rules = %v, want %v", got, tt.wantRules)
+			}
+			if fr.dive != nil || tt.wantDive != nil {
+				if fr.dive == nil || !reflect.DeepEqual(names(fr.dive), tt.wantDive) {
+					t.Errorf("dive = %v, want %v", fr.dive, tt.wantDive)
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterRuleRecompiles(t *testing.T) {
+	type order struct {
+		Quantity int `enforce:"multipleOf:6"`
+	}
+	v := New()
+	var schemaErr *SchemaError
+	if err := v.Check(&order{Quantity: 12}); !errors.As(err, &schemaErr) {
+		t.Fatalf("Check before registering = %v, want a SchemaError", err)
+	}
+
+	err := v.RegisterRule(Rule{
+		Name:  "multipleOf",
+		Kinds: []reflect.Kind{reflect.Int},
+		Parse: func(param string) (interface{}, error) {
+			return strconv.Atoi(param)
+		},
+		Check: func(fc FieldContext) error {
+			if fc.Value.Int()%int64(fc.Parsed.(int)) != 0 {
+				return errors.New("not a multiple")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Check(&order{Quantity: 12}); err != nil {
+		t.Errorf("Check(12) = %v, want nil", err)
+	}
+	if err := v.Check(&order{Quantity: 13}); err == nil {
+		t.Errorf("Check(13) = nil, want a failure")
+	}
+	if err := v.CheckVar(5, "multipleOf:x"); !errors.As(err, &schemaErr) {
+		t.Errorf("CheckVar with a bad parameter = %v, want a SchemaError", err)
+	}
+	// Other validators keep their own rules
+	if err := New().Check(&order{Quantity: 12}); !errors.As(err, &schemaErr) {
+		t.Errorf("Check on another Validator = %v, want a SchemaError", err)
+	}
+}
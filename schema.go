@@ -0,0 +1,272 @@
+package enforcer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rrojan/enforcer/enforcements"
+)
+
+// SchemaError is a mistake in the enforce tags of a struct, such as a bad rule
+// parameter or a default that breaks its own field's rules. It is meant for
+// developers and should never be shown to clients.
+type SchemaError = enforcements.SchemaError
+
+// schema is the compiled form of a struct type's enforce tags
+type schema struct {
+	fields []schemaField
+	// nested holds the indexes of fields that hold structs with fields of their
+	// own, directly or as the elements of a collection
+	nested []int
+	err    error
+	// mutation is reported when a struct passed by value has fields, nested
+	// ones included, that validation changes
+	mutation *SchemaError
+	// registrations is enforcements.Registrations() when the schema was compiled
+	registrations uint64
+}
+
+type schemaField struct {
+	field reflect.StructField
+	index int
+	rules *fieldRules
+}
+
+// Precompile compiles the enforce tags of the given structs, or pointers to
+// them, ahead of their first validation so mistakes show up at startup
+func Precompile(structs ...interface{}) error {
+	return defaultValidator.Precompile(structs...)
+}
+
+// Precompile compiles the tags of the given structs, or pointers to them,
+// ahead of their first validation so mistakes show up at startup
+func (v *Validator) Precompile(structs ...interface{}) error {
+	for _, s := range structs {
+		t := reflect.TypeOf(s)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			return fmt.Errorf("enforcer: struct expected, got %T", s)
+		}
+		if err := v.schemaOf(t).err; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schemaOf returns the cached schema of a struct type, compiling it when the
+// type is first seen or a provider or region was registered since
+func (v *Validator) schemaOf(t reflect.Type) *schema {
+	registrations := enforcements.Registrations()
+	cached, ok := v.schemas.Load(t)
+	if ok && cached.(*schema).registrations == registrations {
+		return cached.(*schema)
+	}
+	compiled := v.compileSchema(t, v.settings(nil), map[reflect.Type]bool{})
+	compiled.registrations = registrations
+	if !ok {
+		cached, _ = v.schemas.LoadOrStore(t, compiled)
+		return cached.(*schema)
+	}
+	v.schemas.Store(t, compiled)
+	return compiled
+}
+
+// compileSchema binds the tags of t to rules and checks them, along with the
+// structs nested in it. seen guards against types that refer to themselves.
+func (v *Validator) compileSchema(t reflect.Type, settings enforcements.Settings, seen map[reflect.Type]bool) *schema {
+	seen[t] = true
+	s := &schema{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup(settings.TagKey())
+		if !ok && strings.Contains(string(field.Tag), settings.TagKey()+":") {
+			// Lookup fails on quoting mistakes such as an unescaped `\s` in a pattern
+			s.err = &SchemaError{Struct: t.Name(), Field: field.Name, Message: "malformed enforce tag, backslashes must be escaped as `\\\\`"}
+			return s
+		}
+		if tag == "" && v.validateTags {
+			var untranslated []string
+			tag, untranslated = TranslateValidateTag(field.Tag.Get(validateTagKey))
+			if len(untranslated) > 0 {
+				s.err = &SchemaError{
+					Struct:  t.Name(),
+					Field:   field.Name,
+					Message: fmt.Sprintf("validate tag can't be translated: %s", strings.Join(untranslated, ", ")),
+				}
+				return s
+			}
+		}
+		if tag == "" {
+			continue
+		}
+		opts := strings.Split(tag, " ")
+		rules, err := v.rules.bind(opts)
+		if err != nil {
+			err.Struct, err.Field = t.Name(), field.Name
+			s.err = err
+			return s
+		}
+		s.fields = append(s.fields, schemaField{field: field, index: i, rules: rules})
+		for _, opt := range opts {
+			if name, ok := changesValue(opt); ok && s.mutation == nil {
+				s.mutation = &SchemaError{
+					Struct:  t.Name(),
+					Field:   field.Name,
+					Rule:    name,
+					Message: fmt.Sprintf("rule '%s' changes the field, so the struct must be passed by pointer", name),
+				}
+			}
+		}
+	}
+	if s.err = checkRules(t, s.fields, settings); s.err != nil {
+		return s
+	}
+	if s.err = checkDefaults(t, s.fields, settings); s.err != nil {
+		return s
+	}
+	// Sanitizers report countries that don't exist even on a zero value
+	if err := enforcements.ApplySanitizersWith(reflect.New(t).Interface(), settings); err != nil {
+		s.err = err
+		return s
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		nested, ok := nestedType(field.Type)
+		if !ok || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		s.nested = append(s.nested, i)
+		if seen[nested] {
+			continue
+		}
+		compiled := v.compileSchema(nested, settings, seen)
+		if compiled.err != nil {
+			s.err = compiled.err
+			return s
+		}
+		if s.mutation == nil {
+			s.mutation = compiled.mutation
+		}
+	}
+	return s
+}
+
+// changesValue reports whether an option changes the field it is on, which
+// only works on a struct passed by pointer, and returns the rule's name
+func changesValue(opt string) (string, bool) {
+	name, param, _ := strings.Cut(opt, ":")
+	switch name {
+	case "default", "prohibit", "toE164":
+		return name, true
+	case "datetime":
+		return name, strings.Contains(param, "into=")
+	}
+	return "", false
+}
+
+// nestedType returns the struct type a field holds directly, through a pointer
+// or as the elements of a slice, array or map
+func nestedType(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		t = t.Elem()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	return t, enforcements.IsNestedStruct(t)
+}
+
+// checkCall validates a fresh value of a struct while compiling its schema
+func checkCall(inst reflect.Value, settings enforcements.Settings) *ruleCall {
+	return &ruleCall{
+		settings: settings,
+		now:      settings.Now(),
+		sibling: func(name string) (reflect.Value, bool) {
+			f := inst.FieldByName(name)
+			return f, f.IsValid()
+		},
+	}
+}
+
+// checkRules runs every rule of every field once against a zero value to catch
+// bad parameters and unsupported types before any client input is seen
+func checkRules(t reflect.Type, fields []schemaField, settings enforcements.Settings) error {
+	call := checkCall(reflect.New(t).Elem(), settings)
+	for _, f := range fields {
+		fieldType := f.field.Type
+		// Rules after `dive` are checked against a zero element
+		for fr := f.rules; fr != nil; fr = fr.dive {
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			var errs ValidationErrors
+			// Without the optional modifiers, which would skip the rules on the zero value
+			call.validateField(&errs, fieldLoc{name: f.field.Name, path: f.field.Name}, reflect.New(fieldType).Elem(), false, &fieldRules{rules: fr.rules})
+			if fr.dive != nil {
+				switch fieldType.Kind() {
+				case reflect.Slice, reflect.Array, reflect.Map:
+					fieldType = fieldType.Elem()
+				default:
+					call.recordMistake(fieldLoc{path: f.field.Name}, "dive", fmt.Sprintf("Unsupported type%s", enforcements.ForField(f.field.Name)))
+				}
+			}
+			if err := call.schemaError(t.Name()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkDefaults applies the literal defaults of t to a fresh value and runs the
+// rules of each defaulted field against it. Values from providers are only
+// known when they are applied, so only their names are checked.
+func checkDefaults(t reflect.Type, fields []schemaField, settings enforcements.Settings) error {
+	inst := reflect.New(t).Elem()
+	call := checkCall(inst, settings)
+
+	defaults := make(map[int]string)
+	for _, f := range fields {
+		value, ok := enforcements.DefaultValue(settings.Tag(f.field))
+		if ok && enforcements.IsProvidedDefault(value) {
+			if err := enforcements.CheckProvidedDefault(value); err != nil {
+				return &SchemaError{Struct: t.Name(), Field: f.field.Name, Rule: "default", Message: err.Error()}
+			}
+			continue
+		}
+		if !ok || !inst.Field(f.index).CanSet() {
+			continue
+		}
+		if err := enforcements.SetDefault(inst.Field(f.index), value, call.settings); err != nil {
+			return &SchemaError{Struct: t.Name(), Field: f.field.Name, Rule: "default", Message: err.Error()}
+		}
+		defaults[f.index] = value
+	}
+
+	for _, f := range fields {
+		value, ok := defaults[f.index]
+		if !ok {
+			continue
+		}
+		var errs ValidationErrors
+		call.validateField(&errs, fieldLoc{name: f.field.Name, path: f.field.Name}, inst.Field(f.index), true, f.rules)
+		if len(errs) > 0 {
+			return &SchemaError{
+				Struct:  t.Name(),
+				Field:   f.field.Name,
+				Rule:    errs[0].Rule,
+				Message: fmt.Sprintf("default '%s' breaks rule '%s': %s", value, errs[0].Rule, errs[0].Message),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package enforcer
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type byValueAddress struct {
+	Phone string `enforce:"toE164:NP"`
+}
+
+func TestByValueStructs(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      interface{}
+		wantRule string
+	}{
+		{"rules only", struct {
+			Name string `enforce:"required"`
+		}{Name: "a"}, ""},
+		{"default", struct {
+			Name string `enforce:"default:a"`
+		}{}, "default"},
+		{"prohibit", struct {
+			Role string `enforce:"prohibit"`
+		}{}, "prohibit"},
+		{"toE164", struct {
+			Phone string `enforce:"toE164:NP"`
+		}{}, "toE164"},
+		{"datetime target", struct {
+			From     string `enforce:"datetime:date,into=FromTime"`
+			FromTime time.Time
+		}{From: "2024-01-01"}, "datetime"},
+		{"datetime without target", struct {
+			From string `enforce:"datetime:date"`
+		}{From: "2024-01-01"}, ""},
+		{"nested", struct {
+			Address byValueAddress
+		}{}, "toE164"},
+		{"nested element", struct {
+			Addresses []byValueAddress
+		}{}, "toE164"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.req)
+			var schemaErr *SchemaError
+			if tt.wantRule == "" {
+				if err != nil {
+					t.Errorf("Check() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.As(err, &schemaErr) || schemaErr.Rule != tt.wantRule {
+				t.Errorf("Check() = %v, want a SchemaError for rule '%s'", err, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestSanitizerSchemaErrors(t *testing.T) {
+	type contact struct {
+		Phone string `enforce:"toE164:XX"`
+	}
+	type company struct {
+		Contacts []contact
+	}
+	for _, req := range []interface{}{&contact{Phone: "9841234567"}, &company{}} {
+		var schemaErr *SchemaError
+		if err := Check(req); !errors.As(err, &schemaErr) || schemaErr.Rule != "toE164" {
+			t.Errorf("Check(%T) = %v, want a toE164 SchemaError", req, err)
+		}
+	}
+}
+
+type brokenTag struct {
+	Age int `enforce:"min:abc"`
+}
+
+func TestSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func() error
+		field string
+		rule  string
+	}{
+		{"bad parameter", func() error { return Check(&brokenTag{}) }, "Age", "min"},
+		{"unknown rule", func() error {
+			return Check(&struct {
+				Name string `enforce:"requried"`
+			}{})
+		}, "Name", "requried"},
+		{"default breaks rule", func() error {
+			return Check(&struct {
+				Size int `enforce:"default:50 between:0,10"`
+			}{})
+		}, "Size", "between"},
+		{"variable", func() error { return CheckVar(5, "min:abc") }, "", "min"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schemaErr *SchemaError
+			err := tt.check()
+			if !errors.As(err, &schemaErr) || schemaErr.Field != tt.field || schemaErr.Rule != tt.rule {
+				t.Errorf("err = %#v, want a SchemaError for %s rule '%s'", err, tt.field, tt.rule)
+			}
+		})
+	}
+}
+
+func TestSchemaErrorsStayOutOfMessages(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	tests := []struct {
+		name     string
+		validate func() []string
+	}{
+		{"Validate", func() []string { return Validate(&brokenTag{}) }},
+		{"ValidateVar", func() []string { return ValidateVar(5, "min:abc") }},
+		{"CustomValidator", func() []string { return CustomValidator(&brokenTag{}, nil) }},
+		{"provider", func() []string {
+			return Validate(&struct {
+				Region string `enforce:"default:@env(ENFORCER_TEST_UNSET)"`
+			}{})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logged.Reset()
+			errs := tt.validate()
+			if len(errs) != 1 || errs[0] != UnavailableMessage {
+				t.Errorf("messages = %q, want only UnavailableMessage", errs)
+			}
+			if !strings.Contains(logged.String(), "enforcer:") {
+				t.Errorf("the mistake was not logged")
+			}
+		})
+	}
+}
+
+func TestPanicOnSchemaErrors(t *testing.T) {
+	v := New(WithFailureMode(PanicOnSchemaErrors))
+	tests := []struct {
+		name     string
+		validate func()
+	}{
+		{"Validate", func() { v.Validate(&brokenTag{}) }},
+		{"Check", func() { _ = v.Check(&brokenTag{}) }},
+		{"ValidateVar", func() { v.ValidateVar(5, "min:abc") }},
+		{"by value", func() {
+			v.Validate(struct {
+				Name string `enforce:"default:a"`
+			}{})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if _, ok := recover().(*SchemaError); !ok {
+					t.Errorf("want a panic with a *SchemaError")
+				}
+			}()
+			tt.validate()
+		})
+	}
+
+	if errs := v.Validate(&struct {
+		Name string `enforce:"required"`
+	}{Name: "a"}); len(errs) != 0 {
+		t.Errorf("valid struct = %q", errs)
+	}
+}
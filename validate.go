@@ -1,137 +1,173 @@
 package enforcer
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
-	"strings"
+	"strconv"
 
 	"github.com/rrojan/enforcer/enforcements"
 )
 
-// Validate fields of a given struct based on `enforce` tags
-func Validate(req interface{}) []string {
-	enforcements.ApplyDefaults(req)
-	v := reflect.ValueOf(req)
-	if v.Kind() == reflect.Ptr {
-		v = v.Elem()
+// Validate fields of a given struct based on `enforce` tags. Mistakes in tags
+// are logged and reported as UnavailableMessage, use Check to get them.
+func Validate(req interface{}, opts ...Option) []string {
+	return defaultValidator.Validate(req, opts...)
+}
+
+// Check validates like Validate but returns the failures as ValidationErrors,
+// or nil when the struct is valid. Mistakes in the struct's tags and defaults
+// are returned as a *SchemaError so they can be told apart from client errors.
+// Check and CheckVar are the only calls that return them.
+func Check(req interface{}, opts ...Option) error {
+	return defaultValidator.Check(req, opts...)
+}
+
+// Validate fields of a given struct based on its tags. Mistakes in tags are
+// logged and reported as UnavailableMessage, use Check to get them.
+func (v *Validator) Validate(req interface{}, opts ...Option) []string {
+	return clientMessages(v.validate(req, opts, v.custom))
+}
+
+// Check validates like Validate but returns the failures as ValidationErrors,
+// or nil when the struct is valid. Mistakes in tags are returned as a *SchemaError.
+func (v *Validator) Check(req interface{}, opts ...Option) error {
+	errs, err := v.validate(req, opts, v.custom)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validate applies defaults, prohibits and sanitizers to a struct and runs the
+// rules of its fields. A struct passed by value can't be changed, so one with
+// rules that change fields is a *SchemaError. custom holds the functions for
+// `custom:` rules.
+func (v *Validator) validate(req interface{}, opts []Option, custom CustomEnforcements) (ValidationErrors, error) {
+	rv := reflect.ValueOf(req)
+	byPointer := rv.Kind() == reflect.Ptr && !rv.IsNil()
+	if byPointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, v.schemaFailure(&SchemaError{Message: fmt.Sprintf("struct or pointer to struct expected, got %T", req)})
+	}
+	if !byPointer {
+		// Rules read unexported fields through their address, which only the
+		// fields of a struct behind a pointer have
+		copied := reflect.New(rv.Type()).Elem()
+		copied.Set(rv)
+		rv = copied
+	}
+	s := v.schemaOf(rv.Type())
+	if s.err != nil {
+		return nil, v.schemaFailure(s.err)
+	}
+	if !byPointer && s.mutation != nil {
+		return nil, v.schemaFailure(s.mutation)
+	}
+
+	settings := v.settings(opts)
+	var prohibited []*FieldError
+	if byPointer {
+		var err error
+		prohibited, err = enforcements.ApplyDefaultsAndProhibits(req, settings)
+		var schemaErr *SchemaError
+		switch {
+		case errors.As(err, &schemaErr):
+			return nil, v.schemaFailure(err)
+		case err != nil:
+			// A provider failed, such as `@env` with the variable unset
+			return nil, err
+		}
+		if err := enforcements.ApplySanitizersWith(req, settings); err != nil {
+			return nil, v.schemaFailure(err)
+		}
 	}
 
-	t := v.Type()
+	call := &ruleCall{
+		settings: settings,
+		now:      settings.Now(),
+		custom:   custom,
+		localize: v.localize,
+	}
+	errs := ValidationErrors(prohibited)
+	for _, err := range errs {
+		v.localize(err, "")
+	}
+	v.validateStruct(&errs, call, rv, s, fieldLoc{})
+	// Some tag mistakes, like a datetime target that can't be set, only show up
+	// with a value
+	if err := call.schemaError(rv.Type().Name()); err != nil {
+		return nil, v.schemaFailure(err)
+	}
 
-	var errors []string
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		enforceTag := field.Tag.Get("enforce")
+	return errs, nil
+}
 
-		if enforceTag != "" {
-			fieldValue := v.Field(i)
-			fieldType := fieldValue.Type()
-			fieldString := fieldValue.String()
-			enforceOpts := strings.Split(enforceTag, " ")
+// validateStruct runs the rules of a struct's fields and of the structs nested
+// in them. loc is where rv sits below the top level struct.
+func (v *Validator) validateStruct(errs *ValidationErrors, call *ruleCall, rv reflect.Value, s *schema, loc fieldLoc) {
+	// Nested structs replace the lookup once the fields of rv are checked
+	call.sibling = func(name string) (reflect.Value, bool) {
+		f := rv.FieldByName(name)
+		return f, f.IsValid()
+	}
+
+	for _, f := range s.fields {
+		fieldValue := rv.Field(f.index)
+		fieldLoc := fieldLocOf(call.settings, f.field, loc)
+		provided := call.settings.Provided(fieldLoc.key, fieldValue)
+		call.validateField(errs, fieldLoc, fieldValue, provided, f.rules)
+	}
 
-			for _, opt := range enforceOpts {
-				switch {
-				case opt == "required":
-					err := enforcements.HandleRequired(fieldValue, field.Name)
-					if err != "" {
-						errors = append(errors, err)
-					}
-				case strings.HasPrefix(opt, "between"):
-					if fieldType.Kind() == reflect.Int {
-						err := enforcements.HandleBetweenInt(fieldValue.Int(), field.Name, opt)
-						if err != "" {
-							errors = append(errors, err)
-						}
-					} else if fieldType.Kind() == reflect.String {
-						err := enforcements.HandleBetweenStr(fieldString, field.Name, opt)
-						if err != "" {
-							errors = append(errors, err)
-						}
-					} else {
-						errors = append(errors, fmt.Sprintf("Unsupported type for field '%s'", field.Name))
-					}
-				case strings.HasPrefix(opt, "min"):
-					if fieldType.Kind() == reflect.Int {
-						err := enforcements.HandleMinInt(fieldValue.Int(), field.Name, opt)
-						if err != "" {
-							errors = append(errors, err)
-						}
-					} else if fieldType.Kind() == reflect.String {
-						err := enforcements.HandleMinStr(fieldString, field.Name, opt)
-						if err != "" {
-							errors = append(errors, err)
-						}
-					} else {
-						errors = append(errors, fmt.Sprintf("Unsupported type for field '%s'", field.Name))
-					}
-				case strings.HasPrefix(opt, "max"):
-					if fieldType.Kind() == reflect.Int {
-						err := enforcements.HandleMaxInt(fieldValue.Int(), field.Name, opt)
-						if err != "" {
-							errors = append(errors, err)
-						}
-					} else if fieldType.Kind() == reflect.String {
-						err := enforcements.HandleMaxStr(fieldString, field.Name, opt)
-						if err != "" {
-							errors = append(errors, err)
-						}
-					} else {
-						errors = append(errors, fmt.Sprintf("Unsupported type for field '%s'", field.Name))
-					}
-				case strings.HasPrefix(opt, "wordCount"):
-					err := enforcements.HandleWordCount(fieldString, field.Name, opt)
-					if err != "" {
-						errors = append(errors, err)
-					}
-				case strings.HasPrefix(opt, "match"):
-					err := enforcements.HandleMatch(fieldString, field.Name, opt)
-					if err != "" {
-						errors = append(errors, err)
-					}
-				case strings.HasPrefix(opt, "enum"):
-					if fieldType.Kind() == reflect.Int {
-						err := enforcements.HandleEnumIntOrFloat(fieldValue.Int(), field.Name, opt)
-						if err != "" {
-							errors = append(errors, err)
-						}
-					} else if fieldType.Kind() == reflect.Float32 || fieldType.Kind() == reflect.Float64 {
-						err := enforcements.HandleEnumIntOrFloat(fieldValue.Float(), field.Name, opt)
-						if err != "" {
-							errors = append(errors, err)
-						}
-					} else if fieldType.Kind() == reflect.String {
-						err := enforcements.HandleEnumStr(fieldString, field.Name, opt)
-						if err != "" {
-							errors = append(errors, err)
-						}
-					} else {
-						errors = append(errors, fmt.Sprintf("Unsupported type for field '%s'", field.Name))
-					}
-				case strings.HasPrefix(opt, "exclude"):
-					if fieldType.Kind() == reflect.Int {
-						err := enforcements.HandleExcludeIntOrFloat(fieldValue.Int(), field.Name, opt)
-						if err != "" {
-							errors = append(errors, err)
-						}
-					} else if fieldType.Kind() == reflect.Float32 || fieldType.Kind() == reflect.Float64 {
-						err := enforcements.HandleExcludeIntOrFloat(fieldValue.Float(), field.Name, opt)
-						if err != "" {
-							errors = append(errors, err)
-						}
-					} else if fieldType.Kind() == reflect.String {
-						err := enforcements.HandleExcludeStr(fieldString, field.Name, opt)
-						if err != "" {
-							errors = append(errors, err)
-						}
-					} else {
-						errors = append(errors, fmt.Sprintf("Unsupported type for field '%s'", field.Name))
-					}
-					// Add additional handlers for other enforcements as required
-					// ...
-				}
+	for _, i := range s.nested {
+		field := rv.Type().Field(i)
+		fieldLoc := fieldLocOf(call.settings, field, loc)
+		// Untagged embedded structs are flattened into their parent
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			fieldLoc = loc
+		}
+
+		fieldValue := rv.Field(i)
+		if fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				continue
+			}
+			fieldValue = fieldValue.Elem()
+		}
+		switch fieldValue.Kind() {
+		case reflect.Struct:
+			v.validateStruct(errs, call, fieldValue, v.schemaOf(fieldValue.Type()), fieldLoc)
+		case reflect.Slice, reflect.Array:
+			for j := 0; j < fieldValue.Len(); j++ {
+				v.validateElement(errs, call, fieldValue.Index(j), fieldLoc.at(strconv.Itoa(j)))
+			}
+		case reflect.Map:
+			iter := fieldValue.MapRange()
+			for iter.Next() {
+				v.validateElement(errs, call, iter.Value(), fieldLoc.at(fmt.Sprint(iter.Key())))
 			}
 		}
 	}
+}
 
-	return errors
+// validateElement validates a struct held in a collection, skipping nil pointers
+func (v *Validator) validateElement(errs *ValidationErrors, call *ruleCall, elem reflect.Value, loc fieldLoc) {
+	if elem.Kind() == reflect.Ptr {
+		if elem.IsNil() {
+			return
+		}
+		elem = elem.Elem()
+	}
+	if !elem.CanAddr() {
+		// Structs held by value in a map have no address of their own
+		copied := reflect.New(elem.Type()).Elem()
+		copied.Set(readable(elem))
+		elem = copied
+	}
+	v.validateStruct(errs, call, elem, v.schemaOf(elem.Type()), loc)
 }
@@ -1,134 +1,64 @@
 package enforcer
 
 import (
-	"fmt"
 	"reflect"
 	"strings"
 
 	"github.com/rrojan/enforcer/enforcements"
 )
 
-// ValidateVar validates an individual variable based on the provided enforcement tag
-func ValidateVar(value interface{}, enforceTag string) []string {
-	v := reflect.ValueOf(value)
-	t := v.Type()
+// ValidateVar validates an individual variable based on the provided enforcement
+// tag. Mistakes in the tag are logged and reported as UnavailableMessage, use
+// CheckVar to get them.
+func ValidateVar(value interface{}, enforceTag string, opts ...Option) []string {
+	return defaultValidator.ValidateVar(value, enforceTag, opts...)
+}
+
+// CheckVar validates like ValidateVar but returns the failures as
+// ValidationErrors, or nil when the value is valid. Mistakes in the tag are
+// returned as a *SchemaError.
+func CheckVar(value interface{}, enforceTag string, opts ...Option) error {
+	return defaultValidator.CheckVar(value, enforceTag, opts...)
+}
 
-	var errors []string
+// ValidateVar validates an individual variable based on the provided rules.
+// Mistakes in the tag are logged and reported as UnavailableMessage.
+func (v *Validator) ValidateVar(value interface{}, enforceTag string, opts ...Option) []string {
+	return clientMessages(v.validateVar(value, enforceTag, opts))
+}
 
-	// Convert the value to a string for validation
-	var fieldValue string
-	if v.Kind() == reflect.String {
-		fieldValue = v.String()
-	} else {
-		fieldValue = fmt.Sprintf("%v", value)
+// CheckVar validates like ValidateVar but returns the failures as
+// ValidationErrors, or nil when the value is valid. Mistakes in the tag are
+// returned as a *SchemaError.
+func (v *Validator) CheckVar(value interface{}, enforceTag string, opts ...Option) error {
+	errs, err := v.validateVar(value, enforceTag, opts)
+	if err != nil {
+		return err
 	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
 
+func (v *Validator) validateVar(value interface{}, enforceTag string, opts []Option) (ValidationErrors, error) {
 	enforceOpts := strings.Split(enforceTag, " ")
+	rules, schemaErr := v.rules.bind(enforceOpts)
+	if schemaErr != nil {
+		return nil, v.schemaFailure(schemaErr)
+	}
+
+	settings := v.settings(opts)
+	call := &ruleCall{settings: settings, now: settings.Now(), custom: v.custom}
+	rv := reflect.ValueOf(value)
 
-	for _, opt := range enforceOpts {
-		switch {
-		case opt == "required":
-			err := enforcements.HandleRequired(v, "")
-			if err != "" {
-				errors = append(errors, err)
-			}
-		case strings.HasPrefix(opt, "between"):
-			if t.Kind() == reflect.Int {
-				err := enforcements.HandleBetweenInt(v.Int(), "", opt)
-				if err != "" {
-					errors = append(errors, err)
-				}
-			} else if t.Kind() == reflect.String {
-				err := enforcements.HandleBetweenStr(fieldValue, "", opt)
-				if err != "" {
-					errors = append(errors, err)
-				}
-			} else {
-				errors = append(errors, fmt.Sprintf("Unsupported type for between enforcement: %s", t.Kind()))
-			}
-		case strings.HasPrefix(opt, "min"):
-			if t.Kind() == reflect.Int {
-				err := enforcements.HandleMinInt(v.Int(), "", opt)
-				if err != "" {
-					errors = append(errors, err)
-				}
-			} else if t.Kind() == reflect.String {
-				err := enforcements.HandleMinStr(fieldValue, "", opt)
-				if err != "" {
-					errors = append(errors, err)
-				}
-			} else {
-				errors = append(errors, fmt.Sprintf("Unsupported type for min enforcement: %s", t.Kind()))
-			}
-		case strings.HasPrefix(opt, "max"):
-			if t.Kind() == reflect.Int {
-				err := enforcements.HandleMaxInt(v.Int(), "", opt)
-				if err != "" {
-					errors = append(errors, err)
-				}
-			} else if t.Kind() == reflect.String {
-				err := enforcements.HandleMaxStr(fieldValue, "", opt)
-				if err != "" {
-					errors = append(errors, err)
-				}
-			} else {
-				errors = append(errors, fmt.Sprintf("Unsupported type for max enforcement: %s", t.Kind()))
-			}
-		case strings.HasPrefix(opt, "match"):
-			err := enforcements.HandleMatch(fieldValue, "", opt)
-			if err != "" {
-				errors = append(errors, err)
-			}
-		case strings.HasPrefix(opt, "wordCount"):
-			err := enforcements.HandleWordCount(fieldValue, "", opt)
-			if err != "" {
-				errors = append(errors, err)
-			}
-		case strings.HasPrefix(opt, "enum"):
-			if t.Kind() == reflect.Int {
-				err := enforcements.HandleEnumIntOrFloat(v.Int(), "", opt)
-				if err != "" {
-					errors = append(errors, err)
-				}
-			} else if t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64 {
-				err := enforcements.HandleEnumIntOrFloat(v.Float(), "", opt)
-				if err != "" {
-					errors = append(errors, err)
-				}
-			} else if t.Kind() == reflect.String {
-				err := enforcements.HandleEnumStr(fieldValue, "", opt)
-				if err != "" {
-					errors = append(errors, err)
-				}
-			} else {
-				errors = append(errors, fmt.Sprintf("Unsupported type for enum enforcement: %s", t.Kind()))
-			}
-		case strings.HasPrefix(opt, "exclude"):
-			if t.Kind() == reflect.Int {
-				err := enforcements.HandleExcludeIntOrFloat(v.Int(), "", opt)
-				if err != "" {
-					errors = append(errors, err)
-				}
-			} else if t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64 {
-				err := enforcements.HandleExcludeIntOrFloat(v.Float(), "", opt)
-				if err != "" {
-					errors = append(errors, err)
-				}
-			} else if t.Kind() == reflect.String {
-				err := enforcements.HandleExcludeStr(fieldValue, "", opt)
-				if err != "" {
-					errors = append(errors, err)
-				}
-			} else {
-				errors = append(errors, fmt.Sprintf("Unsupported type for exclude enforcement: %s", t.Kind()))
-			}
-			// Add additional handlers for other enforcements as required
-			// ...
-		}
+	var errs ValidationErrors
+	call.validateField(&errs, fieldLoc{}, rv, !enforcements.IsEmpty(rv), rules)
+	if err := call.schemaError(""); err != nil {
+		return nil, v.schemaFailure(err)
 	}
-	// Errors for single variable contains `''` because it is not a struct field
-	for i := range errors {
-		errors[i] = strings.ReplaceAll(errors[i], "''", "")
+	for _, err := range errs {
+		v.localize(err, "")
 	}
-	return errors
+	return errs, nil
 }
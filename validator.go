@@ -0,0 +1,197 @@
+package enforcer
+
+import (
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rrojan/enforcer/enforcements"
+)
+
+// Validator validates structs and variables with its own rules, messages and
+// configuration. It is safe for concurrent use by multiple goroutines. The
+// package level functions use a default Validator.
+type Validator struct {
+	rules *registry
+	// schemas caches a *schema per reflect.Type
+	schemas  sync.Map
+	defaults []Option
+	messages map[string]string
+	custom   CustomEnforcements
+	strict   atomic.Bool
+	// validateTags translates `validate` tags on fields without rules of their own
+	validateTags bool
+}
+
+// ValidatorOption configures a Validator created with New
+type ValidatorOption func(*Validator)
+
+// NameMapper returns the name a field is given in messages, or "" to use the
+// Go field name
+type NameMapper func(field reflect.StructField) string
+
+// FailureMode selects what happens when a Validator finds a mistake in tags
+type FailureMode int
+
+const (
+	// ReturnSchemaErrors returns tag mistakes as a *SchemaError
+	ReturnSchemaErrors FailureMode = iota
+	// PanicOnSchemaErrors panics with the *SchemaError at the first use of a
+	// broken struct, so mistakes fail tests rather than reaching clients
+	PanicOnSchemaErrors
+)
+
+// New returns a Validator with the built-in rules and the given options
+func New(opts ...ValidatorOption) *Validator {
+	v := &Validator{rules: newRegistry(builtinRules())}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+var defaultValidator = New()
+
+// WithRules registers rules on the Validator in addition to the built-in ones.
+// It panics when a rule is invalid or its name is taken.
+func WithRules(rules ...Rule) ValidatorOption {
+	return func(v *Validator) {
+		for _, rule := range rules {
+			if err := v.rules.register(rule); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+// WithMessages replaces the messages of failed rules, keyed by rule name.
+// `{field}`, `{param}` and `{rule}` in a message are filled in, e.g.
+// `{"required": "{field} is required"}`.
+func WithMessages(messages map[string]string) ValidatorOption {
+	return func(v *Validator) {
+		v.messages = make(map[string]string, len(messages))
+		for rule, message := range messages {
+			v.messages[rule] = message
+		}
+	}
+}
+
+// WithTagName makes the Validator read rules from another struct tag than `enforce`
+func WithTagName(name string) ValidatorOption {
+	return func(v *Validator) {
+		v.defaults = append(v.defaults, func(s *enforcements.Settings) {
+			s.TagName = name
+		})
+	}
+}
+
+// WithValidateTags makes the Validator also read go-playground/validator
+// `validate` tags, translated with TranslateValidateTag, on fields that have no
+// tag of its own. Parts that can't be translated are reported as a *SchemaError.
+func WithValidateTags() ValidatorOption {
+	return func(v *Validator) {
+		v.validateTags = true
+	}
+}
+
+// WithNameMapper sets how fields are named in messages and error paths, such
+// as JSONNames. Fields with a `label` tag keep their label in messages.
+func WithNameMapper(mapper NameMapper) ValidatorOption {
+	return func(v *Validator) {
+		v.defaults = append(v.defaults, func(s *enforcements.Settings) {
+			s.FieldName = mapper
+		})
+	}
+}
+
+// WithFailureMode sets what the Validator does with mistakes in tags
+func WithFailureMode(mode FailureMode) ValidatorOption {
+	return func(v *Validator) {
+		v.strict.Store(mode == PanicOnSchemaErrors)
+	}
+}
+
+// WithDefaults applies call options, such as WithClock or WithLocation, to
+// every call of the Validator. Options passed to a call take precedence.
+func WithDefaults(opts ...Option) ValidatorOption {
+	return func(v *Validator) {
+		v.defaults = append(v.defaults, opts...)
+	}
+}
+
+// WithCustomEnforcements makes `custom:` rules run the given functions in
+// every call, without passing them to CustomValidator each time
+func WithCustomEnforcements(custom CustomEnforcements) ValidatorOption {
+	return func(v *Validator) {
+		v.custom = append(v.custom, custom...)
+	}
+}
+
+// RegisterRule makes a rule available to the Validator's tags. Structs
+// compiled before are compiled again.
+func (v *Validator) RegisterRule(rule Rule) error {
+	if err := v.rules.register(rule); err != nil {
+		return err
+	}
+	// Schemas may have failed on the missing rule
+	v.schemas.Range(func(key, _ interface{}) bool {
+		v.schemas.Delete(key)
+		return true
+	})
+	return nil
+}
+
+// settings combines the Validator's defaults with the options of a call
+func (v *Validator) settings(opts []Option) enforcements.Settings {
+	var settings enforcements.Settings
+	for _, opt := range v.defaults {
+		opt(&settings)
+	}
+	for _, opt := range opts {
+		opt(&settings)
+	}
+	return settings
+}
+
+// schemaFailure returns err, or panics with it when the Validator panics on
+// schema errors
+func (v *Validator) schemaFailure(err error) error {
+	if v.strict.Load() {
+		panic(err)
+	}
+	return err
+}
+
+// UnavailableMessage is what Validate, ValidateVar and CustomValidator return
+// in place of a mistake in tags or a failing provider, which are meant for
+// developers. The request is not valid when it is returned.
+const UnavailableMessage = "Request could not be validated"
+
+// clientMessages returns the messages of errs. A developer error is logged and
+// reported as UnavailableMessage, so the call fails closed without showing it
+// to clients.
+func clientMessages(errs ValidationErrors, err error) []string {
+	if err != nil {
+		log.Printf("enforcer: %v", err)
+		return []string{UnavailableMessage}
+	}
+	return errs.Messages()
+}
+
+// localize replaces the message of a failed rule from the message catalog
+func (v *Validator) localize(err *FieldError, param string) {
+	message, ok := v.messages[err.Rule]
+	if !ok {
+		return
+	}
+	err.Message = strings.NewReplacer("{field}", err.Field, "{param}", param, "{rule}", err.Rule).Replace(message)
+}
+
+// SetStrict makes the package level functions panic with the *SchemaError
+// instead of returning it, so tag mistakes fail tests at the first use of a
+// struct rather than reaching clients
+func SetStrict(enabled bool) {
+	defaultValidator.strict.Store(enabled)
+}
@@ -0,0 +1,78 @@
+package enforcer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestValidatorOptions(t *testing.T) {
+	type signup struct {
+		UserType string    `json:"type" check:"required enum:admin,user"`
+		Trial    time.Time `json:"trial" check:"after:timeNow"`
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	v := New(
+		WithTagName("check"),
+		WithNameMapper(JSONNames),
+		WithMessages(map[string]string{"enum": "{field} must be one of {param}"}),
+		WithDefaults(WithClock(ClockFunc(func() time.Time { return now }))),
+	)
+
+	got := v.Validate(&signup{UserType: "root", Trial: now.Add(time.Hour)})
+	want := []string{"type must be one of admin,user"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() = %q, want %q", got, want)
+	}
+	// The package level functions read `enforce` tags, which signup has none of
+	if got := Validate(&signup{}); len(got) != 0 {
+		t.Errorf("default Validate() = %q, want no failures", got)
+	}
+}
+
+func TestValidatorConcurrentUse(t *testing.T) {
+	type item struct {
+		Qty int `json:"qty" enforce:"default:1 between:1,10"`
+	}
+	type order struct {
+		ID    string `json:"id" enforce:"required min:3"`
+		Items []item `json:"items"`
+	}
+	v := New(WithNameMapper(JSONNames))
+
+	var wg sync.WaitGroup
+	failures := make(chan string, 64)
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := order{ID: fmt.Sprint(i * 1000), Items: []item{{}, {Qty: i % 12}}}
+			err := v.Check(&req)
+
+			// "0" is too short and 11 is out of range, a zero Qty takes the default
+			wantFailure := i == 0 || i%12 == 11
+			var errs ValidationErrors
+			switch {
+			case req.Items[0].Qty != 1:
+				failures <- fmt.Sprintf("request %d: default not applied", i)
+			case wantFailure != errors.As(err, &errs):
+				failures <- fmt.Sprintf("request %d: Check() = %v", i, err)
+			}
+		}(i)
+		// Registrations recompile schemas while other calls use them
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			RegisterRegion(fmt.Sprintf("concurrent-%d", i), BoundingBox{MaxLat: 1, MaxLng: 1})
+			_ = v.RegisterRule(Rule{Name: fmt.Sprintf("concurrent%d", i), Check: func(fc FieldContext) error { return nil }})
+		}(i)
+	}
+	wg.Wait()
+	close(failures)
+	for failure := range failures {
+		t.Error(failure)
+	}
+}